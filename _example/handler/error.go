@@ -15,3 +15,12 @@ func MethodNotAllowedHandler(w http.ResponseWriter, r *http.Request) {
 	*/
 	http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 }
+
+func PanicHandler(w http.ResponseWriter, r *http.Request, v interface{}) {
+	/*
+		some implementation ...
+	*/
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	w.Write([]byte(`{"error":"Internal Server Error"}`))
+}
@@ -16,7 +16,10 @@ const (
 func main() {
 	fmt.Println("Server Start....")
 	r := NewRouter()
-	r = mw.RequestLog(r)
+	r = mw.RequestLog(r, func(r *http.Request) (*http.Request, func() string) {
+		r, route := WithMatchedRoute(r)
+		return r, func() string { return route.Pattern }
+	})
 	address := fmt.Sprintf("%s:%s", host, port)
 	log.Printf("Server is starting at %s ...", address)
 	if err := http.ListenAndServe(address, r); err != nil {
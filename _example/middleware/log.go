@@ -5,9 +5,19 @@ import (
 	"net/http"
 )
 
-func RequestLog(next http.Handler) http.Handler {
+// RequestLog logs the method and the route pattern of the requests after they are served,
+// so that the requests to the same route are logged alike regardless of the path params.
+// withRoute returns the request holding the route to be matched by the router and the function returning its pattern,
+// which can be built on WithMatchedRoute generated with -routecontext.
+// The path is logged instead of the pattern if no route matches the request.
+func RequestLog(next http.Handler, withRoute func(r *http.Request) (*http.Request, func() string)) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Printf("[%s] %s\n", r.Method, r.URL.Path)
+		r, pattern := withRoute(r)
 		next.ServeHTTP(w, r)
+		if p := pattern(); p != "" {
+			fmt.Printf("[%s] %s\n", r.Method, p)
+			return
+		}
+		fmt.Printf("[%s] %s (no route)\n", r.Method, r.URL.Path)
 	})
 }
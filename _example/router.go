@@ -21,7 +21,7 @@ func NewRouter() http.Handler {
 	r.HandleFunc("/api/products", http.MethodGet, handler.GetProducts)
 	r.HandleFunc("/api/products", http.MethodPost, handler.CreateProducts)
 	r.HandleFunc("/api/users/create", http.MethodPost, handler.CreateUser)
-	r.HandleFunc("/api/users/:user_id", http.MethodGet, handler.GetUser)
+	r.HandleFunc("/api/users/:user_id", http.MethodGet, handler.GetUser).Name("get-user")
 	r.HandleFunc("/api/users/:user_id", http.MethodPatch, handler.UpdateUser)
 	r.HandleFunc("/api/users/:user_id", http.MethodDelete, handler.DeleteUser)
 	r.HandleFunc("/api/users/:user_id/posts", http.MethodGet, handler.GetPosts)
@@ -31,6 +31,7 @@ func NewRouter() http.Handler {
 	r.HandleFunc("/api/users/:user_id/posts/:post_id/aaa/bbb", http.MethodGet, handler.GetPost)
 	r.HandleNotFound(handler.NotFoundHandler)
 	r.HandleMethodNotAllowed(handler.MethodNotAllowedHandler)
+	r.HandlePanic(handler.PanicHandler)
 	/*
 		...
 	*/
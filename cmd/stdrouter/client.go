@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+// runClient writes the Go client package of the routes declared in the router files.
+func runClient(args []string) {
+	fs := flag.NewFlagSet("client", flag.ExitOnError)
+	routerFileName := fs.String("i", "router.go", "router config file name (comma-separated for multiple files)")
+	outputFileName := fs.String("o", filepath.Join("client", "client_gen.go"), "generated client file name")
+	pkg := fs.String("pkg", "", "package name of the client (default: the name of the directory of the output)")
+	templateDir := fs.String("templates", "", "directory of the templates (<name>.tmpl) overriding the built-in ones")
+	fs.Parse(args)
+
+	spec, err := gen.Parse(strings.Split(*routerFileName, ",")...)
+	if err != nil {
+		fatal(err)
+	}
+	var opts gen.Options
+	if *templateDir != "" {
+		opts.Templates, err = gen.LoadTemplates(*templateDir)
+		if err != nil {
+			fatal(fmt.Errorf("failed to load templates: %w", err))
+		}
+	}
+	if *pkg == "" {
+		dir, err := filepath.Abs(filepath.Dir(*outputFileName))
+		if err != nil {
+			fatal(fmt.Errorf("failed to get absolute path: %w", err))
+		}
+		*pkg = strings.Replace(filepath.Base(dir), "-", "", -1)
+	}
+	src, err := gen.GenerateClient(spec, *pkg, opts)
+	if err != nil {
+		fatal(fmt.Errorf("failed to generate Go source of client: %w", err))
+	}
+	if err := os.MkdirAll(filepath.Dir(*outputFileName), 0755); err != nil {
+		fatal(fmt.Errorf("failed to create directory: %w", err))
+	}
+	if err := writeFile(*outputFileName, src); err != nil {
+		fatal(err)
+	}
+	log.Printf("Client generated to %s\n", *outputFileName)
+}
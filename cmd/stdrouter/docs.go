@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+// runDocs writes the reference of the routes declared in the router files.
+func runDocs(args []string) {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	routerFileName := fs.String("i", "router.go", "router config file name (comma-separated for multiple files)")
+	outputFileName := fs.String("o", "", "output file name of the reference (default: stdout)")
+	format := fs.String("format", gen.DocsFormatMarkdown, "format of the reference: markdown")
+	fs.Parse(args)
+
+	spec, err := gen.Parse(strings.Split(*routerFileName, ",")...)
+	if err != nil {
+		fatal(err)
+	}
+	src, err := gen.GenerateDocs(spec, *format)
+	if err != nil {
+		fatal(err)
+	}
+	if *outputFileName == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := writeFile(*outputFileName, src); err != nil {
+		fatal(err)
+	}
+	log.Printf("Reference of the routes generated to %s\n", *outputFileName)
+}
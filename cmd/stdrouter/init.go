@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/build"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+// runInit writes the starter router file to the package in the current directory.
+func runInit(args []string) {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	routerFileName := fs.String("o", "router.go", "router config file name to write")
+	pkg := fs.String("pkg", "", "package name of the router file (default: the package of the Go files in the directory)")
+	fs.Parse(args)
+
+	if err := writeStarter(*routerFileName, *pkg); err != nil {
+		fatal(err)
+	}
+	log.Printf("Router file written to %s\n", *routerFileName)
+}
+
+// writeStarter writes the starter router file of the package named pkg, or the package in the directory if pkg is empty.
+// The existing file is never overwritten.
+func writeStarter(routerFileName, pkg string) error {
+	if _, err := os.Stat(routerFileName); err == nil {
+		return fmt.Errorf("%s already exists", routerFileName)
+	}
+	if pkg == "" {
+		name, err := packageName(filepath.Dir(routerFileName))
+		if err != nil {
+			return err
+		}
+		pkg = name
+	}
+	src, err := gen.GenerateStarter(pkg, gen.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to generate router file: %w", err)
+	}
+	return writeFile(routerFileName, src)
+}
+
+// packageName returns the name of the package of the Go files in dir,
+// or the name of dir without the Go files, which is "main" unless it is an identifier.
+func packageName(dir string) (string, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err == nil {
+		return bp.Name, nil
+	}
+	if _, ok := err.(*build.NoGoError); !ok {
+		return "", fmt.Errorf("failed to find package: %w", err)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	name := strings.ToLower(strings.Replace(filepath.Base(abs), "-", "", -1))
+	if !token.IsIdentifier(name) || token.IsKeyword(name) {
+		return "main", nil
+	}
+	return name, nil
+}
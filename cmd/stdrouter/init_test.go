@@ -0,0 +1,55 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+func TestWriteStarter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stdrouter")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	writeFiles(t, dir, map[string]string{
+		"go.mod":  "module example.com/app\n\ngo 1.13\n",
+		"main.go": "package app\n",
+	})
+
+	router := filepath.Join(dir, "router.go")
+	if err := writeStarter(router, ""); err != nil {
+		t.Fatalf("writeStarter: %v", err)
+	}
+	b, err := ioutil.ReadFile(router)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile: %v", err)
+	}
+	if !strings.Contains(string(b), "package app\n") {
+		t.Errorf("router.go = \n%s, want the package of the directory", b)
+	}
+	if err := writeStarter(router, ""); err == nil {
+		t.Error("writeStarter() error = nil, want the error of the existing file")
+	}
+
+	// the handlers of the starter router file are declared, and the stubs of the routes added to it are appended
+	names, err := scaffold([]string{router}, "handlers.go", gen.Options{})
+	if err != nil || len(names) != 0 {
+		t.Fatalf("scaffold() = %v, %v, want nothing appended", names, err)
+	}
+	src := strings.Replace(string(b), "\tr.HandleNotFound(", "\tr.HandleFunc(\"/users\", http.MethodGet, getUsers)\n\tr.HandleNotFound(", 1)
+	if err := ioutil.WriteFile(router, []byte(src), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile: %v", err)
+	}
+	names, err = scaffold([]string{router}, "handlers.go", gen.Options{})
+	if err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	if want := filepath.Join(dir, "handlers.go"); len(names) != 1 || names[0] != want {
+		t.Errorf("scaffold() = %v, want %s", names, want)
+	}
+}
@@ -1,51 +1,400 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+	"github.com/tetsuzawa/stdrouter/internal/cli"
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
 )
 
 // Usage is a replacement usage function for the flags package.
 func Usage() {
 	fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
 	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nCommands (run %s <command> -h for the flags):\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s%s\n", c.name, c.usage)
+	}
+}
+
+// command is a subcommand of stdrouter, which runs with the arguments following the name.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string)
+}
+
+// commands are the subcommands of stdrouter.
+var commands = []command{
+	{name: "init", usage: "write the starter router file to the package in the current directory", run: runInit},
+	{name: "docs", usage: "write the reference of the routes in the router files", run: runDocs},
+	{name: "tree", usage: "print the tree of the routes built by the generator", run: runTree},
+	{name: "client", usage: "generate the Go client package with a method for every route", run: runClient},
+	{name: "scaffold", usage: "append the stubs of the handlers missing from their packages", run: runScaffold},
 }
 
 var (
-	routerFileName = flag.String("i", "router.go", "router config file name")
+	routerFileName = flag.String("i", "router.go", "router config file name (comma-separated for multiple files)")
 	outputFileName = flag.String("o", "router_gen.go", "generated router file name")
+	check          = flag.Bool("check", false, "check that the generated files are up to date instead of writing them")
+	templateDir    = flag.String("templates", "", "directory of the templates (<name>.tmpl) overriding the built-in ones")
+	jsonOutput     = flag.Bool("json", false, "print the diagnostics of the router files to stdout as a JSON array for editors")
+	watch          = flag.Bool("watch", false, "watch the router files and the handler packages, and regenerate the files on changes")
+	raiseGoMod     = flag.Bool("gomod", false, "raise the go directive of go.mod to the Go version required by the generated code")
+	watchInterval  = flag.Duration("watchinterval", time.Second, "interval of polling the files for changes with -watch")
+
+	// opts are the options of the generation set by the flags in main.
+	opts gen.Options
 )
 
+// output is a generated file.
+type output struct {
+	name string
+	src  []byte
+}
+
 func main() {
 	log.SetFlags(0)
 	log.SetPrefix(fmt.Sprintf("%s: ", os.Args[0]))
+	if len(os.Args) > 1 {
+		for _, c := range commands {
+			if os.Args[1] == c.name {
+				c.run(os.Args[2:])
+				return
+			}
+		}
+	}
 	flag.Usage = Usage
+	cli.RegisterFlags(flag.CommandLine, &opts)
 	flag.Parse()
+	// the flags of the files are written to the go:generate directive with the options
+	var args []string
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "i" || f.Name == "o" || f.Name == "templates" {
+			args = append(args, "-"+f.Name+"="+f.Value.String())
+		}
+	})
+	opts.Directive = cli.Directive(opts, args)
+	defer flushDiagnostics()
+
+	if *watch {
+		if *check {
+			fatal(fmt.Errorf("-watch cannot be used with -check"))
+		}
+		watchFiles(*watchInterval)
+		return
+	}
 
-	cfg, err := Analyze(*routerFileName)
+	_, outputs, err := generate()
 	if err != nil {
-		err = fmt.Errorf("failed to analyze router file: %w", err)
-		log.Fatalln(err)
+		fatal(err)
 	}
-	g := &Generator{}
-	if err := g.Generate(cfg); err != nil {
-		err = fmt.Errorf("failed to generate Go source: %w", err)
-		log.Fatalln(err)
+
+	if *check {
+		// stdout is kept for the JSON array with -json
+		diffOutput := os.Stdout
+		if *jsonOutput {
+			diffOutput = os.Stderr
+		}
+		upToDate := true
+		for _, o := range outputs {
+			diff, err := diffFile(o.name, o.src)
+			if err != nil {
+				fatal(err)
+			}
+			if diff != "" {
+				fmt.Fprint(diffOutput, diff)
+				upToDate = false
+			}
+		}
+		if !upToDate {
+			fatal(fmt.Errorf("generated files are not up to date; run %s to regenerate", strings.Join(checkArgs(), " ")))
+		}
+		return
 	}
 
-	f, err := os.Create(*outputFileName)
-	defer f.Close()
+	for _, o := range outputs {
+		if err := writeFile(o.name, o.src); err != nil {
+			fatal(err)
+		}
+		if filepath.Base(o.name) == "go.mod" {
+			log.Printf("Go directive raised in %s\n", o.name)
+			continue
+		}
+		log.Printf("Router file generated to %s\n", o.name)
+	}
+}
+
+// generate analyzes the router files and returns the spec and the generated files.
+// The warnings are reported, and the problems preventing the generation are returned as the error.
+// The spec is nil if the router files cannot be analyzed.
+func generate() (*gen.RouterSpec, []output, error) {
+	spec, err := gen.Parse(strings.Split(*routerFileName, ",")...)
 	if err != nil {
-		err = fmt.Errorf("failed to create file: %w", err)
-		log.Fatalln(err)
+		return nil, nil, fmt.Errorf("failed to analyze router file: %w", err)
+	}
+	diags := append(spec.Conflicts(), spec.Warnings()...)
+	if diags.HasError() {
+		return spec, nil, fmt.Errorf("conflicting routes in router file: %w", diags)
+	}
+	reportDiagnostics(diags)
+	goDiags, goModOutput, err := checkGoVersion(filepath.Dir(strings.Split(*routerFileName, ",")[0]), opts, *raiseGoMod)
+	if err != nil {
+		return spec, nil, err
+	}
+	reportDiagnostics(goDiags)
+	if *templateDir != "" {
+		opts.Templates, err = gen.LoadTemplates(*templateDir)
+		if err != nil {
+			return spec, nil, fmt.Errorf("failed to load templates: %w", err)
+		}
+	}
+	src, err := gen.Generate(spec, opts)
+	if err != nil {
+		return spec, nil, fmt.Errorf("failed to generate Go source: %w", err)
+	}
+	outputs := []output{{name: *outputFileName, src: src}}
+
+	if opts.Testable {
+		src, err := gen.GenerateTest(spec, opts)
+		if err != nil {
+			return spec, nil, fmt.Errorf("failed to generate Go test source: %w", err)
+		}
+		testFileName := strings.TrimSuffix(*outputFileName, ".go") + "_test.go"
+		outputs = append(outputs, output{name: testFileName, src: src})
+	}
+
+	paramsFiles, err := gen.GenerateParams(spec, opts)
+	if err != nil {
+		return spec, nil, fmt.Errorf("failed to generate Go source of params: %w", err)
+	}
+	for _, f := range paramsFiles {
+		dir, err := packageDir(filepath.Dir(strings.Split(*routerFileName, ",")[0]), f.ImportPath)
+		if err != nil {
+			return spec, nil, err
+		}
+		outputs = append(outputs, output{name: filepath.Join(dir, paramsFileName), src: f.Src})
+	}
+	if goModOutput != nil {
+		outputs = append(outputs, *goModOutput)
 	}
-	_, err = f.Write(g.format())
+	return spec, outputs, nil
+}
+
+// diagnostics are the diagnostics to be printed as JSON with -json.
+var diagnostics = gen.Diagnostics{}
+
+// reportDiagnostics prints the diagnostics to stderr in the format "file:line:col: msg",
+// or keeps them to be printed as JSON with -json.
+func reportDiagnostics(diags gen.Diagnostics) {
+	if *jsonOutput {
+		diagnostics = append(diagnostics, diags...)
+		return
+	}
+	for _, d := range diags {
+		fmt.Fprintln(os.Stderr, d)
+	}
+}
+
+// flushDiagnostics prints the diagnostics kept with -json and clears them.
+func flushDiagnostics() {
+	if !*jsonOutput {
+		return
+	}
+	b, err := json.MarshalIndent(diagnostics, "", "\t")
 	if err != nil {
-		err = fmt.Errorf("failed to write bytes to the file: %w", err)
 		log.Fatalln(err)
 	}
+	fmt.Println(string(b))
+	diagnostics = gen.Diagnostics{}
+}
+
+// fatal reports the error and exits with status 1.
+func fatal(err error) {
+	reportError(err)
+	flushDiagnostics()
+	os.Exit(1)
+}
 
-	log.Printf("Router file generated to %s\n", *outputFileName)
+// reportError reports the error.
+// The diagnostics in the error are reported one by one, and the other errors are reported without the position.
+func reportError(err error) {
+	var diags gen.Diagnostics
+	switch {
+	case errors.As(err, &diags):
+		reportDiagnostics(diags)
+	case *jsonOutput:
+		reportDiagnostics(gen.Diagnostics{{Severity: gen.SeverityError, Message: err.Error()}})
+	default:
+		log.Println(err)
+	}
+}
+
+// paramsFileName is the name of the file declaring the structs of the path params in the package of the handlers.
+const paramsFileName = "params_gen.go"
+
+// packageDir returns the directory of the package of the import path in the module containing dir.
+func packageDir(dir, importPath string) (string, error) {
+	root, modPath, err := stdrouter.FindModule(dir)
+	if err != nil {
+		return "", err
+	}
+	if !stdrouter.InModule(importPath, modPath) {
+		return "", fmt.Errorf("package %s is not in the module %s", importPath, modPath)
+	}
+	return stdrouter.ModuleDir(root, modPath, importPath), nil
+}
+
+// goMod is the go.mod of the module.
+type goMod struct {
+	name string
+	src  []byte
+	// version is the version of the go directive such as "1.22".
+	version string
+}
+
+// findGoMod returns go.mod of the module containing dir, or nil if dir is not in a module.
+func findGoMod(dir string) (*goMod, error) {
+	root, _, err := stdrouter.FindModule(dir)
+	if err != nil {
+		// the version is not known outside the modules
+		return nil, nil
+	}
+	name := filepath.Join(root, "go.mod")
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	// go 1.16 is assumed without the go directive
+	m := &goMod{name: name, src: b, version: "1.16"}
+	for _, line := range strings.Split(string(b), "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "go" {
+			m.version = fields[1]
+		}
+	}
+	return m, nil
+}
+
+// raiseGoDirective returns the source of go.mod with the go directive replaced with the version,
+// or added after the module directive if go.mod has no go directive.
+func raiseGoDirective(src []byte, version string) []byte {
+	lines := strings.Split(string(src), "\n")
+	for i, line := range lines {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "go" {
+			lines[i] = "go " + version
+			return []byte(strings.Join(lines, "\n"))
+		}
+	}
+	for i, line := range lines {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+			lines = append(lines[:i+1], append([]string{"", "go " + version}, lines[i+1:]...)...)
+			break
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// goMinor returns the minor version of Go 1 in the go directive such as "1.22", "1.22.0" or "1.21rc1".
+func goMinor(v string) (int, bool) {
+	if !strings.HasPrefix(v, "1.") {
+		return 0, false
+	}
+	v = v[len("1."):]
+	i := 0
+	for i < len(v) && '0' <= v[i] && v[i] <= '9' {
+		i++
+	}
+	minor, err := strconv.Atoi(v[:i])
+	return minor, err == nil
+}
+
+// checkGoVersion reports the options which do not work with the go directive in go.mod of the module containing dir.
+// With raise, go.mod with the go directive raised to the version required by the options is returned to be written
+// instead of the error. The options which work but do not match the go directive are returned as the warnings.
+func checkGoVersion(dir string, opts gen.Options, raise bool) (gen.Diagnostics, *output, error) {
+	m, err := findGoMod(dir)
+	if err != nil || m == nil {
+		return nil, nil, err
+	}
+	minor, ok := goMinor(m.version)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid go directive in go.mod: %s", m.version)
+	}
+	required := opts.MinGoVersion()
+	if required == "" {
+		return nil, nil, nil
+	}
+	// MinGoVersion returns the version in the form of 1.N
+	requiredMinor, _ := goMinor(required)
+	goVersion, goVersionOK := goMinor(strings.TrimPrefix(opts.GoVersion, "go"))
+	if requiredMinor > minor {
+		if raise {
+			return nil, &output{name: m.name, src: raiseGoDirective(m.src, required)}, nil
+		}
+		if opts.Target == gen.TargetServeMux && !(goVersionOK && goVersion > 22) {
+			// GODEBUG defaults to httpmuxgo121=1 for the main module declaring go 1.21 or earlier,
+			// with which ServeMux matches the patterns such as "GET /users/{id}" literally and the routes are never found.
+			return nil, nil, fmt.Errorf("target servemux requires go 1.22 or later in go.mod, which declares go %s, "+
+				"since net/http.ServeMux ignores the Go 1.22 patterns with the default GODEBUG httpmuxgo121=1 of the older go directive; "+
+				"run with -gomod to raise the go directive of go.mod", m.version)
+		}
+		// the generated files are excluded from the build with the Go versions the module supports
+		msg := fmt.Sprintf("-pathvalue requires go %s", required)
+		if goVersionOK && goVersion == requiredMinor {
+			msg = fmt.Sprintf("-go=%s requires go %s", opts.GoVersion, required)
+		}
+		return nil, nil, fmt.Errorf("%s, which is later than go %s declared in go.mod; run with -gomod to raise the go directive of go.mod", msg, m.version)
+	}
+	if opts.GoVersion != "" && requiredMinor < minor {
+		return gen.Diagnostics{{
+			Severity: gen.SeverityWarning,
+			Message:  fmt.Sprintf("-go=%s is earlier than go %s declared in go.mod, and lowers the language version of the generated files", opts.GoVersion, m.version),
+		}}, nil, nil
+	}
+	return nil, nil, nil
+}
+
+// diffFile returns the unified diff from the existing file to the generated source.
+// A missing file is compared as an empty file.
+func diffFile(name string, src []byte) (string, error) {
+	current, err := ioutil.ReadFile(name)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return stdrouter.UnifiedDiff(name, name+" (generated)", current, src), nil
+}
+
+// checkArgs returns the command line without the -check flag.
+func checkArgs() []string {
+	args := []string{os.Args[0]}
+	for _, arg := range os.Args[1:] {
+		if strings.TrimLeft(arg, "-") == "check" || strings.HasPrefix(strings.TrimLeft(arg, "-"), "check=") {
+			continue
+		}
+		args = append(args, arg)
+	}
+	return args
+}
+
+func writeFile(name string, src []byte) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer f.Close()
+	if _, err = f.Write(src); err != nil {
+		return fmt.Errorf("failed to write bytes to the file: %w", err)
+	}
+	return nil
 }
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+func TestCheckGoVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		gomod    string
+		opts     gen.Options
+		raise    bool
+		wantWarn bool
+		// wantErr is the substring of the error, or empty if no error is expected.
+		wantErr string
+		// wantGoMod is the raised go.mod, or empty if go.mod is not written.
+		wantGoMod string
+	}{
+		{
+			name:  "router with old go directive",
+			gomod: "module example.com/app\n\ngo 1.13\n",
+			opts:  gen.Options{Target: gen.TargetRouter},
+		},
+		{
+			name:    "servemux with old go directive",
+			gomod:   "module example.com/app\n\ngo 1.21\n",
+			opts:    gen.Options{Target: gen.TargetServeMux},
+			wantErr: "target servemux requires go 1.22",
+		},
+		{
+			name:    "servemux without go directive",
+			gomod:   "module example.com/app\n",
+			opts:    gen.Options{Target: gen.TargetServeMux},
+			wantErr: "target servemux requires go 1.22",
+		},
+		{
+			name:      "servemux raising go directive",
+			gomod:     "module example.com/app\n\ngo 1.21\n",
+			opts:      gen.Options{Target: gen.TargetServeMux},
+			raise:     true,
+			wantGoMod: "module example.com/app\n\ngo 1.22\n",
+		},
+		{
+			name:      "servemux adding go directive",
+			gomod:     "module example.com/app\n",
+			opts:      gen.Options{Target: gen.TargetServeMux},
+			raise:     true,
+			wantGoMod: "module example.com/app\n\ngo 1.22\n",
+		},
+		{
+			name:  "servemux with go directive of patch release",
+			gomod: "module example.com/app\n\ngo 1.22.0\n",
+			opts:  gen.Options{Target: gen.TargetServeMux},
+		},
+		{
+			name:  "go version of go directive",
+			gomod: "module example.com/app\n\ngo 1.22\n",
+			opts:  gen.Options{GoVersion: "1.22"},
+		},
+		{
+			name:    "go version later than go directive",
+			gomod:   "module example.com/app\n\ngo 1.21\n",
+			opts:    gen.Options{GoVersion: "1.23"},
+			wantErr: "-go=1.23 requires go 1.23",
+		},
+		{
+			name:    "path value with old go directive",
+			gomod:   "module example.com/app\n\ngo 1.13\n",
+			opts:    gen.Options{PathValue: true},
+			wantErr: "-pathvalue requires go 1.22",
+		},
+		{
+			name:    "path value with go version of old go directive",
+			gomod:   "module example.com/app\n\ngo 1.13\n",
+			opts:    gen.Options{PathValue: true, GoVersion: "1.13"},
+			wantErr: "-pathvalue requires go 1.22",
+		},
+		{
+			name:      "path value raising go directive",
+			gomod:     "module example.com/app\n\ngo 1.13\n\nrequire example.com/lib v1.0.0\n",
+			opts:      gen.Options{PathValue: true, GoVersion: "1.22"},
+			raise:     true,
+			wantGoMod: "module example.com/app\n\ngo 1.22\n\nrequire example.com/lib v1.0.0\n",
+		},
+		{
+			name:  "raising go directive not required",
+			gomod: "module example.com/app\n\ngo 1.23\n",
+			opts:  gen.Options{PathValue: true},
+			raise: true,
+		},
+		{
+			name:     "go version earlier than go directive",
+			gomod:    "module example.com/app\n\ngo 1.22\n",
+			opts:     gen.Options{GoVersion: "1.21"},
+			wantWarn: true,
+		},
+		{
+			name:  "path value with go version of go directive",
+			gomod: "module example.com/app\n\ngo 1.23\n",
+			opts:  gen.Options{PathValue: true},
+		},
+		{
+			name: "servemux outside modules",
+			opts: gen.Options{Target: gen.TargetServeMux},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "stdrouter")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+			if tt.gomod != "" {
+				if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte(tt.gomod), 0644); err != nil {
+					t.Fatalf("ioutil.WriteFile: %v", err)
+				}
+			}
+			got, gomod, err := checkGoVersion(dir, tt.opts, tt.raise)
+			if err != nil {
+				if tt.wantErr == "" || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("checkGoVersion() error = %v, wantErr %q", err, tt.wantErr)
+				}
+				if tt.opts.GoVersion == "" && strings.Contains(err.Error(), "-go") && !strings.Contains(err.Error(), "-gomod") {
+					t.Errorf("checkGoVersion() error = %v, mentions -go which is not set", err)
+				}
+				return
+			}
+			if tt.wantErr != "" {
+				t.Fatalf("checkGoVersion() error = nil, wantErr %q", tt.wantErr)
+			}
+			if (len(got) != 0) != tt.wantWarn {
+				t.Errorf("checkGoVersion() = %v, wantWarn %v", got, tt.wantWarn)
+			}
+			switch {
+			case gomod == nil && tt.wantGoMod != "":
+				t.Errorf("checkGoVersion() go.mod = nil, want %q", tt.wantGoMod)
+			case gomod != nil && tt.wantGoMod == "":
+				t.Errorf("checkGoVersion() go.mod = %q, want nil", gomod.src)
+			case gomod != nil:
+				if want := filepath.Join(dir, "go.mod"); gomod.name != want {
+					t.Errorf("checkGoVersion() go.mod name = %s, want %s", gomod.name, want)
+				}
+				if string(gomod.src) != tt.wantGoMod {
+					t.Errorf("checkGoVersion() go.mod = %q, want %q", gomod.src, tt.wantGoMod)
+				}
+			}
+		})
+	}
+}
+
+func TestMain_checkJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the build of the command in short mode")
+	}
+	dir, err := ioutil.TempDir("", "stdrouter")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	goCmd := filepath.Join(runtime.GOROOT(), "bin", "go")
+	bin := filepath.Join(dir, "stdrouter")
+	if out, err := exec.Command(goCmd, "build", "-o", bin, ".").CombinedOutput(); err != nil {
+		t.Fatalf("go build error = %v: %s", err, out)
+	}
+	routerFile, err := filepath.Abs(filepath.Join("..", "..", "_example", "router.go"))
+	if err != nil {
+		t.Fatalf("filepath.Abs: %v", err)
+	}
+	// the router file generated before is out of date
+	outputFile := filepath.Join(dir, "router_gen.go")
+	if err := ioutil.WriteFile(outputFile, []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile: %v", err)
+	}
+
+	cmd := exec.Command(bin, "-check", "-json", "-i", routerFile, "-o", outputFile)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err == nil {
+		t.Fatalf("stdrouter -check -json error = nil, want exit status 1")
+	}
+	var diags []struct {
+		Severity string `json:"severity"`
+		Message  string `json:"message"`
+	}
+	if err := json.Unmarshal(out, &diags); err != nil {
+		t.Fatalf("json.Unmarshal(stdout) error = %v, stdout = %s", err, out)
+	}
+	if len(diags) != 1 || !strings.Contains(diags[0].Message, "not up to date") {
+		t.Errorf("stdout = %s, want the error of the files out of date", out)
+	}
+	if want := "+++ " + outputFile + " (generated)"; !strings.Contains(stderr.String(), want) {
+		t.Errorf("stderr = %s, want the diff containing %q", stderr.String(), want)
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+// runScaffold appends the stubs of the handlers referenced in the router files and missing from their packages
+// to the file in each package of the handlers. The existing declarations are never changed.
+func runScaffold(args []string) {
+	fs := flag.NewFlagSet("scaffold", flag.ExitOnError)
+	routerFileName := fs.String("i", "router.go", "router config file name (comma-separated for multiple files)")
+	outputFileName := fs.String("o", "handlers.go", "name of the file in the package of the handlers to append the stubs to")
+	var opts gen.Options
+	fs.StringVar(&opts.Params, "params", gen.ParamsPositional, "way to pass the path params to the handlers: positional, context or struct")
+	fs.Parse(args)
+
+	names, err := scaffold(strings.Split(*routerFileName, ","), *outputFileName, opts)
+	if err != nil {
+		fatal(err)
+	}
+	if len(names) == 0 {
+		log.Println("No handler is missing")
+		return
+	}
+	for _, name := range names {
+		log.Printf("Stubs appended to %s\n", name)
+	}
+}
+
+// scaffold appends the stubs of the missing handlers to the file named outputFileName in each package of the handlers,
+// and returns the names of the files written.
+// The handlers of the packages outside the module, such as http.NotFound, are regarded as declared.
+func scaffold(routerFiles []string, outputFileName string, opts gen.Options) ([]string, error) {
+	spec, err := gen.Parse(routerFiles...)
+	if err != nil {
+		return nil, err
+	}
+	routerDir := filepath.Dir(routerFiles[0])
+	// the module is needed only for the handlers qualified by the packages
+	root, modPath, modErr := stdrouter.FindModule(routerDir)
+	// the import paths by the names qualifying the packages in the router files
+	imports := map[string]string{}
+	for _, p := range spec.Imports {
+		name, ok := spec.ImportNames[p]
+		if !ok {
+			name = path.Base(p)
+		}
+		imports[name] = p
+	}
+	pkgs := map[string]*types.Package{}
+	dirs := map[string]string{}
+	var loadErr error
+	declared := func(pkg, name string) bool {
+		if _, ok := pkgs[pkg]; !ok {
+			dir := routerDir
+			if pkg != "" {
+				importPath, ok := imports[pkg]
+				if !ok {
+					loadErr = fmt.Errorf("import of the package %s is not found", pkg)
+					return true
+				}
+				if modErr != nil {
+					loadErr = modErr
+					return true
+				}
+				if !stdrouter.InModule(importPath, modPath) {
+					// the stubs are not appended to the packages outside the module such as net/http
+					pkgs[pkg] = nil
+					return true
+				}
+				dir = stdrouter.ModuleDir(root, modPath, importPath)
+			}
+			p, err := loadPackage(dir)
+			if err != nil {
+				loadErr = err
+				return true
+			}
+			pkgs[pkg], dirs[pkg] = p, dir
+		}
+		return pkgs[pkg] == nil || pkgs[pkg].Scope().Lookup(name) != nil
+	}
+	stubs, err := gen.GenerateStubs(spec, opts, declared)
+	if err == nil {
+		err = loadErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate stubs: %w", err)
+	}
+	var names []string
+	for _, s := range stubs {
+		name := filepath.Join(dirs[s.Package], outputFileName)
+		pkgName := pkgs[s.Package].Name()
+		switch {
+		case s.Package == "":
+			pkgName = spec.PackageName
+		case pkgName == "":
+			// the package without Go files is named after the import path, or the qualifier in the router files
+			pkgName = path.Base(imports[s.Package])
+			if !token.IsIdentifier(pkgName) {
+				pkgName = s.Package
+			}
+		}
+		src, err := appendStubs(name, pkgName, s.Src)
+		if err != nil {
+			return names, err
+		}
+		if err := writeFile(name, src); err != nil {
+			return names, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// loadPackage type-checks the package in dir built without the stdrouter tag, the package of the generated router.
+// The declarations are found even if the package has errors, such as the references to the missing handlers.
+func loadPackage(dir string) (*types.Package, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		if _, ok := err.(*build.NoGoError); !ok {
+			return nil, fmt.Errorf("failed to find package -> %w", err)
+		}
+	}
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse file -> %w", err)
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: importer.Default(), Error: func(error) {}}
+	pkg, _ := conf.Check(bp.ImportPath, fset, files, nil)
+	return pkg, nil
+}
+
+// appendStubs returns the source of the file with the stubs appended, importing net/http if it is not imported.
+// The file of the package named pkg is created if it does not exist.
+func appendStubs(name, pkg string, stubs []byte) ([]byte, error) {
+	src, err := ioutil.ReadFile(name)
+	if os.IsNotExist(err) {
+		src = []byte("package " + pkg + "\n")
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, name, src, parser.ImportsOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file: %w", err)
+	}
+	imported := false
+	for _, spec := range f.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == "net/http" && (spec.Name == nil || spec.Name.Name == "http") {
+			imported = true
+		}
+	}
+	var b bytes.Buffer
+	if imported {
+		b.Write(src)
+	} else {
+		// the import declaration is added after the package clause
+		end := fset.Position(f.Name.End()).Offset
+		b.Write(src[:end])
+		b.WriteString("\n\nimport \"net/http\"\n")
+		b.Write(src[end:])
+	}
+	b.WriteString("\n")
+	b.Write(stubs)
+	res, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format file: %w", err)
+	}
+	return res, nil
+}
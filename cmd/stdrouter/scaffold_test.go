@@ -0,0 +1,109 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+// writeFiles writes the files keyed by the slash-separated names relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, src := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			t.Fatalf("os.MkdirAll: %v", err)
+		}
+		if err := ioutil.WriteFile(name, []byte(src), 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile: %v", err)
+		}
+	}
+}
+
+func TestScaffold(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stdrouter")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	users := "package handlers\n\nimport \"net/http\"\n\nfunc ListUsers(w http.ResponseWriter, r *http.Request) {}\n"
+	writeFiles(t, dir, map[string]string{
+		"go.mod": "module example.com/app\n\ngo 1.13\n",
+		"router.go": `//+build stdrouter
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+
+	h "example.com/app/handlers"
+	"example.com/app/internal/v1"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, index)
+	r.HandleFunc("/users", http.MethodGet, h.ListUsers)
+	r.HandleFunc("/users/:user_id", http.MethodGet, h.GetUser)
+	r.HandleFunc("/status", http.MethodGet, api.GetStatus)
+	r.HandleNotFound(http.NotFound)
+	r.HandleMethodNotAllowed(methodNotAllowed)
+	return r
+}
+`,
+		"main.go":                 "package main\n\nimport \"net/http\"\n\nfunc methodNotAllowed(w http.ResponseWriter, r *http.Request) {}\n",
+		"handlers/users.go":       users,
+		"internal/v1/doc.go":      "// Package api is in the directory named after the version.\npackage api\n",
+		"internal/v1/handlers.go": "package api\n",
+	})
+
+	router := filepath.Join(dir, "router.go")
+	names, err := scaffold([]string{router}, "handlers.go", gen.Options{})
+	if err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	// in the order of the routes in the tree
+	want := []string{
+		filepath.Join(dir, "handlers.go"),
+		filepath.Join(dir, "internal", "v1", "handlers.go"),
+		filepath.Join(dir, "handlers", "handlers.go"),
+	}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("scaffold() = %v, want %v", names, want)
+	}
+	for name, contains := range map[string][]string{
+		"handlers.go":             {"package main\n", "func index(w http.ResponseWriter, r *http.Request) {"},
+		"handlers/handlers.go":    {"package handlers\n", "func GetUser(w http.ResponseWriter, r *http.Request, userId string) {"},
+		"internal/v1/handlers.go": {"package api\n", "func GetStatus(w http.ResponseWriter, r *http.Request) {"},
+	} {
+		b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatalf("ioutil.ReadFile: %v", err)
+		}
+		for _, c := range contains {
+			if !strings.Contains(string(b), c) {
+				t.Errorf("%s = \n%s, want to contain %s", name, b, c)
+			}
+		}
+	}
+	// the existing declarations are never changed
+	b, err := ioutil.ReadFile(filepath.Join(dir, "handlers", "users.go"))
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile: %v", err)
+	}
+	if string(b) != users {
+		t.Errorf("handlers/users.go = \n%s, want unchanged", b)
+	}
+
+	// no handler is missing after the stubs are appended
+	names, err = scaffold([]string{router}, "handlers.go", gen.Options{})
+	if err != nil || len(names) != 0 {
+		t.Errorf("scaffold() = %v, %v, want nothing appended", names, err)
+	}
+}
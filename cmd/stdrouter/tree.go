@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+// runTree writes the tree of the routes declared in the router files as the generator builds it.
+func runTree(args []string) {
+	fs := flag.NewFlagSet("tree", flag.ExitOnError)
+	routerFileName := fs.String("i", "router.go", "router config file name (comma-separated for multiple files)")
+	outputFileName := fs.String("o", "", "output file name of the tree (default: stdout)")
+	format := fs.String("format", gen.TreeFormatText, "format of the tree: text or dot (Graphviz)")
+	fs.Parse(args)
+
+	spec, err := gen.Parse(strings.Split(*routerFileName, ",")...)
+	if err != nil {
+		fatal(err)
+	}
+	src, err := gen.GenerateTree(spec, *format)
+	if err != nil {
+		fatal(err)
+	}
+	if *outputFileName == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := writeFile(*outputFileName, src); err != nil {
+		fatal(err)
+	}
+	log.Printf("Tree of the routes generated to %s\n", *outputFileName)
+}
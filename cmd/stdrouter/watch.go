@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+// watchFiles regenerates the files whenever the router files, the templates or the packages of the handlers change,
+// polling them at the interval until the process is interrupted.
+// The problems are reported on every change without exiting.
+func watchFiles(interval time.Duration) {
+	log.Printf("Watching %s for changes\n", *routerFileName)
+	var spec *gen.RouterSpec
+	var last string
+	for {
+		if s := snapshot(watchedFiles(spec)); s != last {
+			if sp := regenerate(); sp != nil {
+				spec = sp
+			}
+			flushDiagnostics()
+			// take the snapshot after writing so that the generated files do not trigger the generation again.
+			last = snapshot(watchedFiles(spec))
+		}
+		time.Sleep(interval)
+	}
+}
+
+// regenerate generates the files and writes only the files which differ from the existing ones.
+// It returns the spec of the router files, or nil if they cannot be analyzed.
+func regenerate() *gen.RouterSpec {
+	spec, outputs, err := generate()
+	if err != nil {
+		reportError(err)
+		return spec
+	}
+	for _, o := range outputs {
+		if current, err := ioutil.ReadFile(o.name); err == nil && bytes.Equal(current, o.src) {
+			continue
+		}
+		if err := writeFile(o.name, o.src); err != nil {
+			reportError(err)
+			continue
+		}
+		log.Printf("Router file generated to %s\n", o.name)
+	}
+	return spec
+}
+
+// watchedFiles returns the files to watch: the router files, the templates and the Go files in the directory
+// of the router files and in the directories of the packages in the module imported by the router files.
+func watchedFiles(spec *gen.RouterSpec) []string {
+	routerFiles := strings.Split(*routerFileName, ",")
+	files := append([]string(nil), routerFiles...)
+	dir := filepath.Dir(routerFiles[0])
+	dirs := []string{dir}
+	if *templateDir != "" {
+		dirs = append(dirs, *templateDir)
+	}
+	if spec != nil {
+		for _, importPath := range spec.Imports {
+			// the packages out of the module such as net/http are not watched.
+			if d, err := packageDir(dir, importPath); err == nil {
+				dirs = append(dirs, d)
+			}
+		}
+	}
+	for _, d := range dirs {
+		infos, err := ioutil.ReadDir(d)
+		if err != nil {
+			continue
+		}
+		for _, info := range infos {
+			if ext := filepath.Ext(info.Name()); !info.IsDir() && (ext == ".go" || ext == ".tmpl") {
+				files = append(files, filepath.Join(d, info.Name()))
+			}
+		}
+	}
+	return files
+}
+
+// snapshot returns the string identifying the modification times and the sizes of the files.
+func snapshot(files []string) string {
+	var b strings.Builder
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			fmt.Fprintf(&b, "%s: missing\n", f)
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %d %d\n", f, info.ModTime().UnixNano(), info.Size())
+	}
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package gen
+
+import (
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+// clientMethod is the method of the generated client sending the request to a route.
+type clientMethod struct {
+	Name    string
+	Method  string
+	Pattern string
+	// Params is the parameter list of the path params such as "userID, postID string".
+	Params string
+	// Path is the Go expression of the path built from the path params.
+	Path string
+	// Doc are the lines of the doc comment of the route following an empty line.
+	Doc []string
+}
+
+// GenerateClient generates the Go source of the client package named pkg,
+// which has a method sending the request to each route in the spec, such as
+// GetPost(ctx context.Context, userID, postID string, opts ...RequestOption) (*http.Response, error).
+// The path params are escaped and joined to the path as written in the pattern, and only net/http is used.
+// The methods are named after the names of the routes given with Name, or after the handlers.
+// The methods of the same name are numbered in the order of the routes, such as GetPost and GetPost2.
+func GenerateClient(spec *RouterSpec, pkg string, opts Options) ([]byte, error) {
+	if err := checkOptions(opts); err != nil {
+		return nil, err
+	}
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name: %s", pkg)
+	}
+	cfg, err := newConfig(spec)
+	if err != nil {
+		return nil, fmt.Errorf("newConfig -> %w", err)
+	}
+	g := &generator{Options: opts}
+	t, err := g.parseTpl("Client")
+	if err != nil {
+		return nil, err
+	}
+	data := struct {
+		Package string
+		Methods []clientMethod
+	}{
+		Package: pkg,
+		Methods: collectClientMethods(cfg),
+	}
+	if err := g.writeTpl(t, data); err != nil {
+		return nil, err
+	}
+	return g.format()
+}
+
+// collectClientMethods returns the methods of the client in the order of the routes.
+// The route registered again with the same method and pattern is replaced by the later one as in the router.
+func collectClientMethods(cfg *config) []clientMethod {
+	last := map[string]int{}
+	for i, rt := range cfg.Routes {
+		last[rt.Method+" "+rt.Pattern] = i
+	}
+	used := map[string]int{}
+	var methods []clientMethod
+	for i, rt := range cfg.Routes {
+		if last[rt.Method+" "+rt.Pattern] != i {
+			continue
+		}
+		name := clientMethodName(rt)
+		used[name]++
+		if n := used[name]; n > 1 {
+			name += strconv.Itoa(n)
+		}
+		var args []string
+		for _, p := range rt.Params() {
+			args = append(args, clientArgName(p))
+		}
+		m := clientMethod{
+			Name:    name,
+			Method:  rt.Method,
+			Pattern: rt.Pattern,
+			Path:    clientPath(rt.Pattern, args),
+		}
+		if len(args) > 0 {
+			m.Params = strings.Join(args, ", ") + " string"
+		}
+		if rt.Doc != "" {
+			m.Doc = append([]string{""}, strings.Split(rt.Doc, "\n")...)
+		}
+		methods = append(methods, m)
+	}
+	return methods
+}
+
+// clientMethodName returns the name of the method of the client for the route:
+// the name of the route such as "get-user" converted to GetUser, or the name of the handler function.
+func clientMethodName(rt Route) string {
+	if rt.Name != "" {
+		name := stdrouter.SnakeToGoName(strings.Map(func(r rune) rune {
+			if r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' {
+				return r
+			}
+			return '_'
+		}, rt.Name))
+		if token.IsIdentifier(name) {
+			return name
+		}
+	}
+	return stdrouter.SnakeToGoName(rt.Handler.Func)
+}
+
+// clientArgName returns the name of the argument for the path param such as userID for user_id.
+// The keywords and the names of the other parameters are suffixed with "Param".
+func clientArgName(param string) string {
+	words := strings.SplitN(param, "_", 2)
+	name := strings.ToLower(words[0])
+	if len(words) == 2 {
+		name += stdrouter.SnakeToGoName(words[1])
+	}
+	if token.IsKeyword(name) || name == "c" || name == "ctx" || name == "opts" || !token.IsIdentifier(name) {
+		name += "Param"
+	}
+	return name
+}
+
+// clientPath returns the Go expression of the path of the pattern, where the path params are replaced by the args
+// escaped with url.PathEscape.
+func clientPath(pattern string, args []string) string {
+	var exprs []string
+	lit := ""
+	for _, s := range strings.Split(pattern, "/")[1:] {
+		lit += "/"
+		if !strings.HasPrefix(s, ":") {
+			lit += s
+			continue
+		}
+		exprs = append(exprs, strconv.Quote(lit), "url.PathEscape("+args[0]+")")
+		args, lit = args[1:], ""
+	}
+	if lit != "" {
+		exprs = append(exprs, strconv.Quote(lit))
+	}
+	return strings.Join(exprs, " + ")
+}
@@ -0,0 +1,111 @@
+package gen
+
+import (
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+func TestGenerateClient(t *testing.T) {
+	spec, err := Parse(filepath.Join("testdata", "router.go"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	got, err := GenerateClient(spec, "client", Options{})
+	if err != nil {
+		t.Fatalf("GenerateClient: %v", err)
+	}
+	golden := filepath.Join("testdata", "client_gen.golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile: %v", err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile: %v", err)
+	}
+	if diff := stdrouter.UnifiedDiff(golden, "got", want, got); diff != "" {
+		t.Errorf("output differs from the golden file; run go test -update to update it:\n%s", diff)
+	}
+	imp := &srcImporter{fset: token.NewFileSet(), pkgs: map[string]*types.Package{}}
+	if _, err := imp.check("client", []string{string(got)}); err != nil {
+		t.Errorf("type-check of the generated client: %v", err)
+	}
+}
+
+func TestGenerateClient_doc(t *testing.T) {
+	spec := &RouterSpec{
+		Routes: []Route{
+			{Method: "GET", Pattern: "/users/:id", Handler: Handler{Func: "getUser"}, Doc: "getUser returns the user.\nIt is cached."},
+		},
+	}
+	got, err := GenerateClient(spec, "client", Options{})
+	if err != nil {
+		t.Fatalf("GenerateClient: %v", err)
+	}
+	want := `// GetUser sends GET /users/:id.
+//
+// getUser returns the user.
+// It is cached.
+func (c *Client) GetUser(ctx context.Context, id string, opts ...RequestOption) (*http.Response, error) {
+	return c.do(ctx, "GET", "/users/"+url.PathEscape(id), opts)
+}
+`
+	if !strings.Contains(string(got), want) {
+		t.Errorf("GenerateClient() =\n%s\nwant the method:\n%s", got, want)
+	}
+
+	if _, err := GenerateClient(spec, "my-client", Options{}); err == nil {
+		t.Errorf("GenerateClient() with invalid package name: want error")
+	}
+}
+
+func TestClientArgName(t *testing.T) {
+	tests := []struct {
+		param string
+		want  string
+	}{
+		{param: "id", want: "id"},
+		{param: "user_id", want: "userID"},
+		{param: "post_url_id", want: "postURLID"},
+		{param: "type", want: "typeParam"},
+		{param: "ctx", want: "ctxParam"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.param, func(t *testing.T) {
+			if got := clientArgName(tt.param); got != tt.want {
+				t.Errorf("clientArgName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		args    []string
+		want    string
+	}{
+		{pattern: "/", want: `"/"`},
+		{pattern: "/api/users", want: `"/api/users"`},
+		{pattern: "/api/users/:user_id", args: []string{"userID"}, want: `"/api/users/" + url.PathEscape(userID)`},
+		{
+			pattern: "/users/:user_id/posts/:post_id/comments",
+			args:    []string{"userID", "postID"},
+			want:    `"/users/" + url.PathEscape(userID) + "/posts/" + url.PathEscape(postID) + "/comments"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := clientPath(tt.pattern, tt.args); got != tt.want {
+				t.Errorf("clientPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
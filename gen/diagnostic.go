@@ -0,0 +1,90 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+// Severity is the severity of the diagnostic.
+type Severity string
+
+// Severities of the diagnostics.
+const (
+	// SeverityError is the problem which prevents the generation.
+	SeverityError Severity = "error"
+	// SeverityWarning is the problem which does not prevent the generation.
+	SeverityWarning Severity = "warning"
+)
+
+// Diagnostic is a problem found in the router file.
+type Diagnostic struct {
+	// Pos is the position of the problem. It is invalid if the problem is not in the router file.
+	Pos      token.Position
+	Severity Severity
+	Message  string
+}
+
+// String returns the diagnostic in the format "file:line:col: msg".
+// The message of the warning is prefixed with "warning: ".
+func (d Diagnostic) String() string {
+	msg := d.Message
+	if d.Severity == SeverityWarning {
+		msg = "warning: " + msg
+	}
+	if !d.Pos.IsValid() {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", d.Pos, msg)
+}
+
+// MarshalJSON encodes the diagnostic as the object with the file, line, column, severity and message.
+func (d Diagnostic) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		File     string   `json:"file"`
+		Line     int      `json:"line"`
+		Column   int      `json:"column"`
+		Severity Severity `json:"severity"`
+		Message  string   `json:"message"`
+	}{
+		File:     d.Pos.Filename,
+		Line:     d.Pos.Line,
+		Column:   d.Pos.Column,
+		Severity: d.Severity,
+		Message:  d.Message,
+	})
+}
+
+// Diagnostics is the list of the problems found in the router files.
+// Parse returns it as the error if any of them is SeverityError.
+type Diagnostics []Diagnostic
+
+// Error returns the diagnostics one per line.
+func (ds Diagnostics) Error() string {
+	var lines []string
+	for _, d := range ds {
+		lines = append(lines, d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HasError reports whether any of the diagnostics is SeverityError.
+func (ds Diagnostics) HasError() bool {
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// posError is the error at the position in the router file.
+type posError struct {
+	pos token.Position
+	msg string
+}
+
+func (e *posError) Error() string {
+	return fmt.Sprintf("%s: %s", e.pos, e.msg)
+}
@@ -0,0 +1,73 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Formats of the reference of the routes.
+const (
+	// DocsFormatMarkdown writes the reference in Markdown.
+	DocsFormatMarkdown = "markdown"
+)
+
+// GenerateDocs returns the reference of the routes in the spec in the format.
+// The routes are grouped by the first two segments of the patterns before the path params, such as "/api/users",
+// and documented with the method, the pattern, the handler, the path params and the doc comment in the router file.
+func GenerateDocs(spec *RouterSpec, format string) ([]byte, error) {
+	switch format {
+	case "", DocsFormatMarkdown:
+	default:
+		return nil, fmt.Errorf("unknown docs format: %s", format)
+	}
+	var prefixes []string
+	sections := make(map[string][]Route)
+	for _, rt := range spec.Routes {
+		prefix := docsSection(rt.Pattern)
+		if _, ok := sections[prefix]; !ok {
+			prefixes = append(prefixes, prefix)
+		}
+		sections[prefix] = append(sections[prefix], rt)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("<!-- Code generated by stdrouter docs. DO NOT EDIT. -->\n\n")
+	b.WriteString("# API Reference\n")
+	for _, prefix := range prefixes {
+		fmt.Fprintf(&b, "\n## %s\n", prefix)
+		for _, rt := range sections[prefix] {
+			fmt.Fprintf(&b, "\n### `%s %s`\n\n", rt.Method, rt.Pattern)
+			if rt.Doc != "" {
+				fmt.Fprintf(&b, "%s\n\n", rt.Doc)
+			}
+			fmt.Fprintf(&b, "- Handler: `%s`\n", rt.Handler)
+			if rt.Name != "" {
+				fmt.Fprintf(&b, "- Name: `%s`\n", rt.Name)
+			}
+			if params := rt.Params(); len(params) > 0 {
+				fmt.Fprintf(&b, "- Parameters: `%s`\n", strings.Join(params, "`, `"))
+			}
+		}
+	}
+	if len(spec.Mounts) > 0 {
+		b.WriteString("\n## Mounts\n\n| Prefix | Handler |\n| --- | --- |\n")
+		for _, m := range spec.Mounts {
+			fmt.Fprintf(&b, "| `%s` | `%s` |\n", m.Prefix, m.Handler)
+		}
+	}
+	return b.Bytes(), nil
+}
+
+// docsSection returns the prefix of the section of the pattern in the reference:
+// the first two segments of the pattern before the path params.
+func docsSection(pattern string) string {
+	var segments []string
+	for _, s := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if s == "" || strings.HasPrefix(s, ":") || len(segments) == 2 {
+			break
+		}
+		segments = append(segments, s)
+	}
+	return "/" + strings.Join(segments, "/")
+}
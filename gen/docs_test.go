@@ -0,0 +1,58 @@
+package gen
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+func TestGenerateDocs(t *testing.T) {
+	spec, err := Parse(filepath.Join("testdata", "router_docs.go"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	got, err := GenerateDocs(spec, DocsFormatMarkdown)
+	if err != nil {
+		t.Fatalf("GenerateDocs: %v", err)
+	}
+	golden := filepath.Join("testdata", "docs.golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, got, 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile: %v", err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile: %v", err)
+	}
+	if diff := stdrouter.UnifiedDiff(golden, "got", want, got); diff != "" {
+		t.Errorf("output differs from the golden file; run go test -update to update it:\n%s", diff)
+	}
+
+	if _, err := GenerateDocs(spec, "html"); err == nil {
+		t.Errorf("GenerateDocs() with unknown format: want error")
+	}
+}
+
+func TestDocsSection(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    string
+	}{
+		{pattern: "/", want: "/"},
+		{pattern: "/api", want: "/api"},
+		{pattern: "/api/users", want: "/api/users"},
+		{pattern: "/api/users/:user_id/posts", want: "/api/users"},
+		{pattern: "/users/:user_id", want: "/users"},
+		{pattern: "/:lang/docs", want: "/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if got := docsSection(tt.pattern); got != tt.want {
+				t.Errorf("docsSection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+// Package gen generates the http router from the router file.
+//
+// Parse reads the route table declared in the router file into RouterSpec,
+// and Generate writes the Go source of the router from it.
+// RouterSpec can also be built or modified by the program before generation.
+//
+//	spec, err := gen.Parse("router.go")
+//	if err != nil {
+//		return err
+//	}
+//	src, err := gen.Generate(spec, gen.Options{})
+package gen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Generation targets.
+const (
+	// TargetRouter generates the router which dispatches requests with switch statements.
+	TargetRouter = "router"
+	// TargetServeMux generates the registrations to net/http.ServeMux with Go 1.22 patterns.
+	TargetServeMux = "servemux"
+)
+
+// Ways to pass the path params to the handlers.
+const (
+	// ParamsPositional passes the path params as the string arguments following w and r.
+	ParamsPositional = "positional"
+	// ParamsContext passes the path params in the context of the request.
+	// The handlers are http.HandlerFunc, and the generated functions Param and Params return the path params.
+	ParamsContext = "context"
+	// ParamsStruct passes the path params as the struct named "<handler>Params" following w and r,
+	// such as GetPostParams{UserID, PostID string}.
+	// The structs are declared in the package of the handlers, which GenerateParams generates.
+	ParamsStruct = "struct"
+)
+
+// Formats of the responses of the built-in NotFound and MethodNotAllowed handlers.
+const (
+	// ErrorFormatText replies with the plain text as http.NotFound and http.Error.
+	ErrorFormatText = "text"
+	// ErrorFormatJSON replies with the JSON object such as {"error":"Not Found"}.
+	ErrorFormatJSON = "json"
+	// ErrorFormatProblem replies with the problem details of RFC 7807 as application/problem+json.
+	ErrorFormatProblem = "problem"
+)
+
+// Options configures the generation.
+type Options struct {
+	// Target is the kind of the generated code. The default is TargetRouter.
+	Target string
+	// Testable makes the router store the matched route in the context of the request as RouteContext does,
+	// so that the test generated by GenerateTest can check the route matching each request.
+	Testable bool
+	// Params is the way to pass the path params to the handlers. The default is ParamsPositional.
+	Params string
+	// PathValue makes the router set the path params with Request.SetPathValue before calling the handlers,
+	// so that the handlers can read them with Request.PathValue. It requires Go 1.22.
+	PathValue bool
+	// GoVersion is the minimum Go version of the generated code such as "1.22".
+	// It is written to the build constraint, which also sets the language version of the generated files.
+	// The version required by the other options is used if it is higher.
+	GoVersion string
+	// RouteTable generates the table of the routes Routes and the function LookupRoute
+	// to inspect the routes at runtime.
+	RouteTable bool
+	// RouteContext makes the router store the matched route in the context of the request,
+	// and generates the functions RoutePattern, RouteName and WithMatchedRoute to read it.
+	RouteContext bool
+	// Metrics makes the router count the requests, the 4xx and 5xx responses and the latency of every route,
+	// and publishes them in the expvar "stdrouter" under MetricsName keyed by the method and the pattern.
+	// The requests to the NotFound and MethodNotAllowed handlers are counted as "NotFound" and "MethodNotAllowed",
+	// and the requests passed to the handlers mounted with Mount are counted as "Mount" and the prefix.
+	Metrics bool
+	// MetricsName is the name of the metrics of the router in the expvar "stdrouter".
+	// The default is the package name of the router. The routers in a binary must have different names.
+	MetricsName string
+	// ErrorFormat is the format of the responses of the built-in NotFound and MethodNotAllowed handlers,
+	// which are generated unless the router file declares them. The default is ErrorFormatText.
+	ErrorFormat string
+	// Interface generates the interface Handlers with a method for each handler function of the routes,
+	// and NewRouter takes the implementation of it, such as NewRouter(h Handlers).
+	// The methods are named after the handler functions, qualified by the packages if the names conflict.
+	// The NotFound, MethodNotAllowed and panic handlers are called as declared in the router file.
+	// It cannot be used with Testable, since the handlers can be replaced by passing the fakes to NewRouter.
+	Interface bool
+	// Templates overrides the built-in templates by name. See LoadTemplates for the names.
+	Templates map[string]string
+	// Directive is the command of the go:generate directive written to the generated router to regenerate it,
+	// such as "stdrouter -tests". The directive is omitted if it is empty.
+	Directive string
+}
+
+// MinGoVersion returns the minimum Go version of the generated code such as "1.22", which is written to the build constraint.
+// It is GoVersion or the version required by the other options if it is higher, and "" if no version is required.
+func (opts Options) MinGoVersion() string {
+	minor, ok := minorVersion(opts.GoVersion)
+	if opts.Target == TargetServeMux || opts.PathValue {
+		// Request.PathValue and the method and wildcard patterns of ServeMux are added in Go 1.22.
+		if !ok || minor < 22 {
+			minor, ok = 22, true
+		}
+	}
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("1.%d", minor)
+}
+
+// checkOptions reports the invalid options.
+func checkOptions(opts Options) error {
+	switch opts.Params {
+	case "", ParamsPositional, ParamsContext, ParamsStruct:
+	default:
+		return fmt.Errorf("unknown params: %s", opts.Params)
+	}
+	switch opts.ErrorFormat {
+	case "", ErrorFormatText, ErrorFormatJSON, ErrorFormatProblem:
+	default:
+		return fmt.Errorf("unknown error format: %s", opts.ErrorFormat)
+	}
+	if opts.Interface && opts.Testable {
+		return fmt.Errorf("interface cannot be used with tests")
+	}
+	if opts.GoVersion != "" {
+		if _, ok := minorVersion(opts.GoVersion); !ok {
+			return fmt.Errorf("invalid Go version: %s", opts.GoVersion)
+		}
+	}
+	for name := range opts.Templates {
+		if _, ok := builtinTpls[name]; !ok {
+			return fmt.Errorf("unknown template: %s", name)
+		}
+	}
+	return nil
+}
+
+// minorVersion returns the minor version of Go 1 in the version such as "1.22" or "go1.22".
+func minorVersion(v string) (int, bool) {
+	v = strings.TrimPrefix(v, "go")
+	if !strings.HasPrefix(v, "1.") {
+		return 0, false
+	}
+	minor, err := strconv.Atoi(v[len("1."):])
+	if err != nil || minor < 0 {
+		return 0, false
+	}
+	return minor, true
+}
+
+// Generate generates the Go source of the router.
+// If the generated source is not valid Go, it returns the unformatted source with the error.
+func Generate(spec *RouterSpec, opts Options) ([]byte, error) {
+	if err := checkOptions(opts); err != nil {
+		return nil, err
+	}
+	cfg, err := newConfig(spec)
+	if err != nil {
+		return nil, fmt.Errorf("newConfig -> %w", err)
+	}
+	g := &generator{Options: opts}
+	if err := g.generate(cfg); err != nil {
+		return nil, err
+	}
+	return g.format()
+}
+
+// GenerateTest generates the Go source of the test which sends a request to every route
+// of the router generated with Options.Testable and checks the matched route.
+// The requests are served by the handlers, and the ones matching no route are checked against
+// the status code written by the NotFound or MethodNotAllowed handler expected to respond.
+func GenerateTest(spec *RouterSpec, opts Options) ([]byte, error) {
+	if err := checkOptions(opts); err != nil {
+		return nil, err
+	}
+	cfg, err := newConfig(spec)
+	if err != nil {
+		return nil, fmt.Errorf("newConfig -> %w", err)
+	}
+	g := &generator{Options: opts}
+	if err := g.generateTest(cfg); err != nil {
+		return nil, err
+	}
+	return g.format()
+}
+
+// ParamsFile is the Go source declaring the structs of the path params in a package of the handlers.
+type ParamsFile struct {
+	// Package is the name of the package qualifying the handlers in the router file.
+	Package string
+	// ImportPath is the import path of the package.
+	ImportPath string
+	// Src is the Go source.
+	Src []byte
+}
+
+// GenerateParams generates the Go source declaring the structs of the path params for each package of the handlers
+// other than the package of the router file, whose structs are declared in the router.
+// It returns nothing unless Options.Params is ParamsStruct.
+func GenerateParams(spec *RouterSpec, opts Options) ([]ParamsFile, error) {
+	if err := checkOptions(opts); err != nil {
+		return nil, err
+	}
+	if opts.Params != ParamsStruct {
+		return nil, nil
+	}
+	cfg, err := newConfig(spec)
+	if err != nil {
+		return nil, fmt.Errorf("newConfig -> %w", err)
+	}
+	structs, err := collectParamsStructs(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("collectParamsStructs -> %w", err)
+	}
+	var files []ParamsFile
+	for _, pkg := range paramsPkgs(structs) {
+		if pkg == "" {
+			continue
+		}
+		importPath, ok := findImport(cfg.ImportedPkgs, cfg.ImportNames, pkg)
+		if !ok {
+			return nil, fmt.Errorf("import of the package %s is not found", pkg)
+		}
+		g := &generator{Options: opts}
+		if err := g.generateParamsFile(structs, pkg); err != nil {
+			return nil, err
+		}
+		src, err := g.format()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, ParamsFile{Package: pkg, ImportPath: importPath, Src: src})
+	}
+	return files, nil
+}
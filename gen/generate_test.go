@@ -0,0 +1,872 @@
+package gen
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestGenerate_golden(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		opts   Options
+		test   bool
+		golden string
+	}{
+		{
+			name:   "router",
+			opts:   Options{Directive: "stdrouter"},
+			golden: "router_gen.golden",
+		},
+		{
+			name:   "testable router",
+			opts:   Options{Testable: true},
+			golden: "router_gen_testable.golden",
+		},
+		{
+			name:   "router test",
+			opts:   Options{},
+			test:   true,
+			golden: "router_gen_test.golden",
+		},
+		{
+			name:   "router with params in context",
+			opts:   Options{Testable: true, Params: ParamsContext},
+			golden: "router_gen_context.golden",
+		},
+		{
+			name:   "router test with params in context",
+			opts:   Options{Params: ParamsContext},
+			test:   true,
+			golden: "router_gen_context_test.golden",
+		},
+		{
+			name:   "router with path values",
+			opts:   Options{PathValue: true},
+			golden: "router_gen_pathvalue.golden",
+		},
+		{
+			name:   "router with route table",
+			opts:   Options{RouteTable: true},
+			golden: "router_gen_routes.golden",
+		},
+		{
+			name:   "router with route context",
+			opts:   Options{RouteContext: true},
+			golden: "router_gen_route_context.golden",
+		},
+		{
+			name:   "servemux with route context",
+			opts:   Options{Target: TargetServeMux, RouteContext: true},
+			golden: "servemux_gen_route_context.golden",
+		},
+		{
+			name:   "router with metrics",
+			opts:   Options{Metrics: true},
+			golden: "router_gen_metrics.golden",
+		},
+		{
+			name:   "servemux with metrics",
+			opts:   Options{Target: TargetServeMux, Metrics: true},
+			golden: "servemux_gen_metrics.golden",
+		},
+		{
+			name:   "router with mounts",
+			input:  "router_mount.go",
+			opts:   Options{},
+			golden: "router_gen_mount.golden",
+		},
+		{
+			name:   "servemux with mounts",
+			input:  "router_mount.go",
+			opts:   Options{Target: TargetServeMux},
+			golden: "servemux_gen_mount.golden",
+		},
+		{
+			name:   "router with groups",
+			input:  "router_group.go",
+			opts:   Options{Testable: true},
+			golden: "router_gen_group.golden",
+		},
+		{
+			name:   "router test with groups",
+			input:  "router_group.go",
+			opts:   Options{},
+			test:   true,
+			golden: "router_gen_group_test.golden",
+		},
+		{
+			name:   "servemux with groups",
+			input:  "router_group.go",
+			opts:   Options{Target: TargetServeMux},
+			golden: "servemux_gen_group.golden",
+		},
+		{
+			name:   "router with default handlers in JSON",
+			input:  "router_default.go",
+			opts:   Options{Testable: true, ErrorFormat: ErrorFormatJSON},
+			golden: "router_gen_default.golden",
+		},
+		{
+			name:   "router test with default handlers",
+			input:  "router_default.go",
+			opts:   Options{},
+			test:   true,
+			golden: "router_gen_default_test.golden",
+		},
+		{
+			name:   "servemux with default handlers in problem details",
+			input:  "router_default.go",
+			opts:   Options{Target: TargetServeMux, ErrorFormat: ErrorFormatProblem},
+			golden: "servemux_gen_default.golden",
+		},
+		{
+			name:   "router without routes",
+			input:  "router_empty.go",
+			opts:   Options{},
+			golden: "router_gen_empty.golden",
+		},
+		{
+			name:   "router with params structs",
+			opts:   Options{Testable: true, Params: ParamsStruct},
+			golden: "router_gen_struct.golden",
+		},
+		{
+			name:   "router test with params structs",
+			opts:   Options{Params: ParamsStruct},
+			test:   true,
+			golden: "router_gen_struct_test.golden",
+		},
+		{
+			name:   "servemux",
+			opts:   Options{Target: TargetServeMux},
+			golden: "servemux_gen.golden",
+		},
+		{
+			name:   "router with handlers interface",
+			opts:   Options{Interface: true},
+			golden: "router_gen_interface.golden",
+		},
+		{
+			name:   "servemux with handlers interface",
+			input:  "router_default.go",
+			opts:   Options{Target: TargetServeMux, Interface: true},
+			golden: "servemux_gen_interface.golden",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.input == "" {
+				tt.input = "router.go"
+			}
+			// the output must be the same every time the router file is generated
+			var outputs [][]byte
+			for i := 0; i < 10; i++ {
+				spec, err := Parse(filepath.Join("testdata", tt.input))
+				if err != nil {
+					t.Fatalf("Parse: %v", err)
+				}
+				var src []byte
+				if tt.test {
+					src, err = GenerateTest(spec, tt.opts)
+				} else {
+					src, err = Generate(spec, tt.opts)
+				}
+				if err != nil {
+					t.Fatalf("Generate: %v", err)
+				}
+				outputs = append(outputs, src)
+			}
+			for i, got := range outputs[1:] {
+				if !bytes.Equal(got, outputs[0]) {
+					t.Fatalf("output #%d differs from the first one:\n%s", i+1, stdrouter.UnifiedDiff("first", "got", outputs[0], got))
+				}
+			}
+
+			golden := filepath.Join("testdata", tt.golden)
+			if *update {
+				if err := ioutil.WriteFile(golden, outputs[0], 0644); err != nil {
+					t.Fatalf("ioutil.WriteFile: %v", err)
+				}
+			}
+			want, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("ioutil.ReadFile: %v", err)
+			}
+			if diff := stdrouter.UnifiedDiff(golden, "got", want, outputs[0]); diff != "" {
+				t.Errorf("output differs from the golden file; run go test -update to update it:\n%s", diff)
+			}
+
+			// the golden file must also compile with the handlers declared as the router file references them
+			spec, err := Parse(filepath.Join("testdata", tt.input))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			srcs := [][]byte{outputs[0]}
+			if tt.test {
+				opts := tt.opts
+				opts.Testable = true
+				src, err := Generate(spec, opts)
+				if err != nil {
+					t.Fatalf("Generate: %v", err)
+				}
+				srcs = append(srcs, src)
+			}
+			typeCheck(t, spec, tt.opts, srcs...)
+		})
+	}
+}
+
+func TestGenerate_routing(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []Route
+		want   string
+	}{
+		{
+			name: "root without path params",
+			routes: []Route{
+				{Method: "GET", Pattern: "/", Handler: Handler{Func: "getRoot"}},
+				{Method: "GET", Pattern: "/users", Handler: Handler{Func: "getUsers"}},
+			},
+			// the last handler function is not taken for the path param at the root
+			want: "\tcase \"/\":\n\t\tswitch r.Method {\n\t\tcase http.MethodGet:\n\t\t\tgetRoot(w, r)\n",
+		},
+		{
+			name: "path param without children",
+			routes: []Route{
+				{Method: "GET", Pattern: "/users/:user_id", Handler: Handler{Func: "getUser"}},
+			},
+			// the endpoint of the path param is separated from the paths under it
+			want: "\tendpoint, p := SeparatePath(p, 1)\n\tswitch endpoint {\n\tcase \"/\":\n\t\tswitch r.Method {\n\t\tcase http.MethodGet:\n\t\t\tgetUser(w, r, userId)\n",
+		},
+		{
+			name: "paths under path param",
+			routes: []Route{
+				{Method: "GET", Pattern: "/users/:user_id/aaa/bbb", Handler: Handler{Func: "getBbb"}},
+			},
+			// the case is the path from the path param, not the one with the base path removed
+			want: "\tcase \"/aaa/bbb\":\n\t\tswitch r.Method {\n\t\tcase http.MethodGet:\n\t\t\tgetBbb(w, r, userId)\n",
+		},
+		{
+			name: "endpoint without methods",
+			routes: []Route{
+				{Method: "GET", Pattern: "/api/users", Handler: Handler{Func: "getUsers"}},
+			},
+			// the endpoint only leading to the routes is not found rather than the method not allowed
+			want: "\tcase \"/api\":\n\t\tnotFound(w, r)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &RouterSpec{
+				PackageName:      "main",
+				RouterName:       "r",
+				Imports:          []string{"net/http"},
+				Routes:           tt.routes,
+				NotFound:         &Handler{Func: "notFound"},
+				MethodNotAllowed: &Handler{Func: "methodNotAllowed"},
+			}
+			got, err := Generate(spec, Options{})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if !bytes.Contains(got, []byte(tt.want)) {
+				t.Errorf("Generate() = \n%s, want to contain %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_testable(t *testing.T) {
+	spec, err := Parse(filepath.Join("testdata", "router_group.go"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	for _, target := range []string{TargetRouter, TargetServeMux} {
+		testable, err := Generate(spec, Options{Target: target, Testable: true})
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		routeContext, err := Generate(spec, Options{Target: target, RouteContext: true})
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		// the test reads the matched route, and the router has no seam for the test
+		if !bytes.Equal(testable, routeContext) {
+			t.Errorf("Generate() of %s with Testable = \n%s, want the same as with RouteContext\n%s", target, testable, routeContext)
+		}
+	}
+}
+
+func TestGenerate_builtinHandlers(t *testing.T) {
+	spec := &RouterSpec{
+		PackageName: "main",
+		RouterName:  "r",
+		Imports:     []string{"net/http", "github.com/tetsuzawa/stdrouter"},
+		Routes: []Route{
+			{Method: "GET", Pattern: "/users", Handler: Handler{Func: "getUsers"}},
+			{Method: "GET", Pattern: "/api/users", Handler: Handler{Func: "getAPIUsers"}},
+		},
+		NotFound:               &Handler{Package: "stdrouter", Func: "NotFound"},
+		MethodNotAllowed:       &Handler{Func: "methodNotAllowed"},
+		ScopedMethodNotAllowed: []ScopedHandler{{Prefix: "/api", Handler: Handler{Package: "stdrouter", Func: "MethodNotAllowed"}}},
+	}
+	if warnings := spec.Warnings(); len(warnings) != 0 {
+		t.Errorf("Warnings() = %v, want nothing for the built-in handlers declared", warnings)
+	}
+	got, err := Generate(spec, Options{ErrorFormat: ErrorFormatJSON})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, want := range []string{
+		"func defaultNotFound(w http.ResponseWriter, r *http.Request) {",
+		"func defaultMethodNotAllowed(w http.ResponseWriter, r *http.Request) {",
+		// the Allow header is set for the built-in handler of the group only
+		"\t\t\tw.Header().Set(\"Allow\", \"GET\")\n\t\t\tdefaultMethodNotAllowed(w, r)\n",
+		"\t\tdefault:\n\t\t\tmethodNotAllowed(w, r)\n",
+	} {
+		if !bytes.Contains(got, []byte(want)) {
+			t.Errorf("Generate() = \n%s, want to contain %q", got, want)
+		}
+	}
+	if bytes.Contains(got, []byte("stdrouter.")) {
+		t.Errorf("Generate() = \n%s, want not to refer to the stdrouter package", got)
+	}
+}
+
+func TestGenerate_importNames(t *testing.T) {
+	spec, err := Parse(filepath.Join("testdata", "router_imports.go"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	wantNames := map[string]string{
+		"github.com/tetsuzawa/stdrouter/_example/handler":         "h",
+		"github.com/tetsuzawa/stdrouter/gen/testdata/go-handlers": "handlers",
+		"example.com/admin/v2":                                    "admin",
+	}
+	if !reflect.DeepEqual(spec.ImportNames, wantNames) {
+		t.Errorf("Parse() ImportNames = %v, want %v", spec.ImportNames, wantNames)
+	}
+	for name, want := range map[string]string{
+		"h":        "github.com/tetsuzawa/stdrouter/_example/handler",
+		"handlers": "github.com/tetsuzawa/stdrouter/gen/testdata/go-handlers",
+		"admin":    "example.com/admin/v2",
+		"v2":       "",
+	} {
+		if got, _ := findImport(spec.Imports, spec.ImportNames, name); got != want {
+			t.Errorf("findImport(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		// gen generates the router or its test.
+		gen     func(*RouterSpec, Options) ([]byte, error)
+		want    []string
+		notWant []string
+	}{
+		{
+			name: "router",
+			gen:  Generate,
+			want: []string{
+				"\th \"github.com/tetsuzawa/stdrouter/_example/handler\"\n",
+				"\thandlers \"github.com/tetsuzawa/stdrouter/gen/testdata/go-handlers\"\n",
+				"\tadmin \"example.com/admin/v2\"\n",
+			},
+		},
+		{
+			name: "interface",
+			opts: Options{Interface: true},
+			gen:  Generate,
+			want: []string{
+				// the package of the NotFound handler and the mounted package are used
+				"\th \"github.com/tetsuzawa/stdrouter/_example/handler\"\n",
+				"\tadmin \"example.com/admin/v2\"\n",
+			},
+			// the package only qualifying the handlers of the routes is not used
+			notWant: []string{"go-handlers"},
+		},
+		{
+			name: "test",
+			gen:  GenerateTest,
+			want: []string{"\th \"github.com/tetsuzawa/stdrouter/_example/handler\"\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.gen(spec, tt.opts)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !bytes.Contains(got, []byte(want)) {
+					t.Errorf("got = \n%s, want to contain %q", got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if bytes.Contains(got, []byte(notWant)) {
+					t.Errorf("got = \n%s, want not to contain %q", got, notWant)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_metrics(t *testing.T) {
+	if testing.Short() {
+		t.Skip("the generated routers are built and run")
+	}
+	handlers := "import \"net/http\"\n\nfunc getRoot(w http.ResponseWriter, r *http.Request) {}\n"
+	admin := &RouterSpec{
+		PackageName: "admin",
+		RouterName:  "r",
+		Imports:     []string{"net/http"},
+		Routes:      []Route{{Method: "GET", Pattern: "/", Handler: Handler{Func: "getRoot"}}},
+	}
+	api := &RouterSpec{
+		PackageName: "api",
+		RouterName:  "r",
+		Imports:     []string{"net/http", "example.com/app/admin"},
+		Routes:      []Route{{Method: "GET", Pattern: "/", Handler: Handler{Func: "getRoot"}}},
+		Mounts:      []Mount{{Prefix: "/admin", Handler: "admin.NewRouter()"}},
+	}
+	mainSrc := `package main
+
+import (
+	"expvar"
+	"fmt"
+	"net/http/httptest"
+
+	"example.com/app/admin"
+	"example.com/app/api"
+)
+
+func main() {
+	api.NewRouter().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	api.NewRouter().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/admin/", nil))
+	admin.NewRouter().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	fmt.Print(expvar.Get("stdrouter").String())
+}
+`
+	tests := []struct {
+		name      string
+		adminOpts Options
+		want      map[string]map[string]int
+		wantErr   string
+	}{
+		{
+			name: "routers of packages",
+			want: map[string]map[string]int{
+				// the requests to the mounted router are counted in both routers
+				"api":   {"GET /": 1, "Mount /admin": 1, "NotFound": 0},
+				"admin": {"GET /": 2, "NotFound": 0},
+			},
+		},
+		{
+			name:      "routers of same name",
+			adminOpts: Options{MetricsName: "api"},
+			wantErr:   `metrics "api" of another router are already published`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "stdrouter")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+			files := map[string]string{
+				"go.mod":        "module example.com/app\n\ngo 1.13\n",
+				"main.go":       mainSrc,
+				"admin/root.go": "package admin\n\n" + handlers,
+				"api/root.go":   "package api\n\n" + handlers,
+			}
+			for _, r := range []struct {
+				spec *RouterSpec
+				opts Options
+			}{{admin, tt.adminOpts}, {api, Options{}}} {
+				opts := r.opts
+				opts.Metrics = true
+				src, err := Generate(r.spec, opts)
+				if err != nil {
+					t.Fatalf("Generate() error = %v", err)
+				}
+				files[r.spec.PackageName+"/router_gen.go"] = string(src)
+			}
+			for name, src := range files {
+				name = filepath.Join(dir, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+					t.Fatalf("os.MkdirAll: %v", err)
+				}
+				if err := ioutil.WriteFile(name, []byte(src), 0644); err != nil {
+					t.Fatalf("ioutil.WriteFile: %v", err)
+				}
+			}
+			cmd := exec.Command(filepath.Join(runtime.GOROOT(), "bin", "go"), "run", ".")
+			cmd.Dir = dir
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			out, err := cmd.Output()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(stderr.String(), tt.wantErr) {
+					t.Fatalf("go run error = %v, stderr = %s, want %s", err, stderr.String(), tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("go run error = %v, stderr = %s", err, stderr.String())
+			}
+			var got map[string]map[string]struct {
+				Requests int `json:"requests"`
+			}
+			if err := json.Unmarshal(out, &got); err != nil {
+				t.Fatalf("json.Unmarshal(%s): %v", out, err)
+			}
+			for name, keys := range tt.want {
+				for key, want := range keys {
+					m, ok := got[name][key]
+					if !ok {
+						t.Errorf("metrics %s of %s are not published in %s", key, name, out)
+						continue
+					}
+					if m.Requests != want {
+						t.Errorf("requests of %s of %s = %d, want %d", key, name, m.Requests, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_templates(t *testing.T) {
+	spec := &RouterSpec{
+		PackageName: "main",
+		RouterName:  "r",
+		Imports:     []string{"net/http", "github.com/tetsuzawa/stdrouter/_example/handler"},
+		Routes: []Route{
+			{Method: "GET", Pattern: "/api/users/:user_id", Handler: Handler{Package: "handler", Func: "GetUser"}},
+		},
+		NotFound:         &Handler{Package: "handler", Func: "NotFoundHandler"},
+		MethodNotAllowed: &Handler{Package: "handler", Func: "MethodNotAllowedHandler"},
+	}
+	tests := []struct {
+		name    string
+		opts    Options
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "override handler call",
+			opts: Options{Templates: map[string]string{
+				"HandlerCall": `trace({{ printf "%q" .Method }}, {{ printf "%q" .Pattern }}, func() { {{ .Func }}({{ .Args }}) })`,
+			}},
+			want: `trace("GET", "/api/users/:user_id", func() { handler.GetUser(w, r, userId) })`,
+		},
+		{
+			name: "override handler call of servemux",
+			opts: Options{Target: TargetServeMux, Templates: map[string]string{
+				"HandlerCall": `trace({{ printf "%q" .Handler }}, func() { {{ .Func }}({{ .Args }}) })`,
+			}},
+			want: `trace("handler.GetUser", func() { handler.GetUser(w, r, r.PathValue("user_id")) })`,
+		},
+		{
+			name: "override helper function",
+			opts: Options{Templates: map[string]string{
+				"SeparatePath":     "endpoint, {{ .Tail }} := splitPath({{ .Base }}, {{ .Num }})\n",
+				"SeparatePathFunc": "func splitPath(p string, n int) (string, string) { return p, \"\" }\n",
+			}},
+			want: `endpoint, p := splitPath(p, 3)`,
+		},
+		{
+			name:    "unknown template",
+			opts:    Options{Templates: map[string]string{"Routers": ""}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(spec, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Contains(got, []byte(tt.want)) {
+				t.Errorf("Generate() = \n%s, want to contain %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate_goVersion(t *testing.T) {
+	spec := &RouterSpec{
+		PackageName:      "main",
+		RouterName:       "r",
+		Imports:          []string{"net/http"},
+		Routes:           []Route{{Method: "GET", Pattern: "/users/:user_id", Handler: Handler{Func: "getUser"}}},
+		NotFound:         &Handler{Func: "notFound"},
+		MethodNotAllowed: &Handler{Func: "methodNotAllowed"},
+	}
+	tests := []struct {
+		name    string
+		opts    Options
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no constraint",
+			opts: Options{},
+			want: "//go:build !stdrouter\n",
+		},
+		{
+			name: "go version",
+			opts: Options{GoVersion: "1.21"},
+			want: "//go:build !stdrouter && go1.21\n",
+		},
+		{
+			name: "go version required by path value",
+			opts: Options{PathValue: true, GoVersion: "1.13"},
+			want: "//go:build !stdrouter && go1.22\n",
+		},
+		{
+			name: "go version higher than required by servemux",
+			opts: Options{Target: TargetServeMux, GoVersion: "go1.23"},
+			want: "//go:build !stdrouter && go1.23\n",
+		},
+		{
+			name:    "invalid go version",
+			opts:    Options{GoVersion: "2"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(spec, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Contains(got, []byte(tt.want)) {
+				t.Errorf("Generate() = \n%s, want to contain %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptions_MinGoVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "no version",
+			opts: Options{},
+			want: "",
+		},
+		{
+			name: "go version",
+			opts: Options{GoVersion: "go1.21"},
+			want: "1.21",
+		},
+		{
+			name: "servemux",
+			opts: Options{Target: TargetServeMux, GoVersion: "1.13"},
+			want: "1.22",
+		},
+		{
+			name: "go version higher than required by path value",
+			opts: Options{PathValue: true, GoVersion: "1.23"},
+			want: "1.23",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.MinGoVersion(); got != tt.want {
+				t.Errorf("Options.MinGoVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateParams(t *testing.T) {
+	spec, err := Parse(filepath.Join("testdata", "router.go"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	files, err := GenerateParams(spec, Options{Params: ParamsStruct})
+	if err != nil {
+		t.Fatalf("GenerateParams: %v", err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("GenerateParams() returned %d files, want 1", len(files))
+	}
+	if files[0].Package != "handler" || files[0].ImportPath != "github.com/tetsuzawa/stdrouter/_example/handler" {
+		t.Errorf("GenerateParams() = %s %s, want handler github.com/tetsuzawa/stdrouter/_example/handler", files[0].Package, files[0].ImportPath)
+	}
+	golden := filepath.Join("testdata", "params_gen.golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, files[0].Src, 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile: %v", err)
+		}
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile: %v", err)
+	}
+	if diff := stdrouter.UnifiedDiff(golden, "got", want, files[0].Src); diff != "" {
+		t.Errorf("output differs from the golden file; run go test -update to update it:\n%s", diff)
+	}
+
+	// the handlers in the package of the router file have the structs in the router
+	files, err = GenerateParams(spec, Options{})
+	if err != nil || files != nil {
+		t.Errorf("GenerateParams() = %v, %v, want nothing without ParamsStruct", files, err)
+	}
+}
+
+func TestGenerate_paramsStructConflict(t *testing.T) {
+	spec := &RouterSpec{
+		PackageName: "main",
+		RouterName:  "r",
+		Imports:     []string{"net/http"},
+		Routes: []Route{
+			{Method: "GET", Pattern: "/users/:user_id", Handler: Handler{Func: "get"}},
+			{Method: "GET", Pattern: "/posts/:post_id", Handler: Handler{Func: "get"}},
+		},
+		NotFound:         &Handler{Func: "notFound"},
+		MethodNotAllowed: &Handler{Func: "methodNotAllowed"},
+	}
+	if _, err := Generate(spec, Options{Params: ParamsStruct}); err == nil {
+		t.Error("Generate() error = nil, want the error of the handler with different path params")
+	}
+	if _, err := Generate(spec, Options{}); err != nil {
+		t.Errorf("Generate() error = %v, want nil with positional params", err)
+	}
+}
+
+// fakePkgs are the packages imported by the router files in testdata other than the packages of the handlers.
+var fakePkgs = map[string]string{
+	"example.com/admin": "package admin\n\nimport \"net/http\"\n\nfunc NewRouter() http.Handler { return nil }\n",
+}
+
+// stdImporter imports the standard library for typeCheck, which is shared to load each package once.
+var stdImporter = importer.Default()
+
+// srcImporter imports the packages of the handlers type-checked from the sources,
+// and the standard library with stdImporter.
+type srcImporter struct {
+	fset *token.FileSet
+	srcs map[string][]string
+	pkgs map[string]*types.Package
+}
+
+func (imp *srcImporter) Import(path string) (*types.Package, error) {
+	if p, ok := imp.pkgs[path]; ok {
+		return p, nil
+	}
+	srcs, ok := imp.srcs[path]
+	if !ok {
+		return stdImporter.Import(path)
+	}
+	p, err := imp.check(path, srcs)
+	if err != nil {
+		return nil, err
+	}
+	imp.pkgs[path] = p
+	return p, nil
+}
+
+func (imp *srcImporter) check(path string, srcs []string) (*types.Package, error) {
+	var files []*ast.File
+	for i, src := range srcs {
+		f, err := parser.ParseFile(imp.fset, fmt.Sprintf("%s/%d.go", path, i), src, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: imp}
+	return conf.Check(path, imp.fset, files, nil)
+}
+
+// typeCheck type-checks the generated files of the package of the router file
+// with the stubs of the handlers generated by GenerateStubs and the params structs generated by GenerateParams.
+// It is skipped if the generated files require the newer Go than the running one.
+func typeCheck(t *testing.T, spec *RouterSpec, opts Options, srcs ...[]byte) {
+	t.Helper()
+	for _, src := range srcs {
+		if i := bytes.Index(src, []byte(" && go1.")); i >= 0 {
+			v := string(src[i+len(" && ") : i+bytes.IndexByte(src[i:], '\n')])
+			if !releaseTag(v) {
+				t.Skipf("the generated files require %s", v)
+			}
+		}
+	}
+	imp := &srcImporter{fset: token.NewFileSet(), srcs: map[string][]string{}, pkgs: map[string]*types.Package{}}
+	for path, src := range fakePkgs {
+		imp.srcs[path] = []string{src}
+	}
+	// the stubs of the functions declared as the handlers, which the interface of the handlers also calls
+	stubOpts := opts
+	stubOpts.Interface = false
+	stubs, err := GenerateStubs(spec, stubOpts, func(pkg, name string) bool { return false })
+	if err != nil {
+		t.Fatalf("GenerateStubs: %v", err)
+	}
+	paramsFiles, err := GenerateParams(spec, opts)
+	if err != nil {
+		t.Fatalf("GenerateParams: %v", err)
+	}
+	var files []string
+	for _, src := range srcs {
+		files = append(files, string(src))
+	}
+	for _, s := range stubs {
+		pkg := s.Package
+		if pkg == "" {
+			files = append(files, "package "+spec.PackageName+"\n\nimport \"net/http\"\n\n"+string(s.Src))
+			continue
+		}
+		path, ok := findImport(spec.Imports, spec.ImportNames, pkg)
+		if !ok {
+			t.Fatalf("import of the package %s is not found", pkg)
+		}
+		imp.srcs[path] = append(imp.srcs[path], "package "+pkg+"\n\nimport \"net/http\"\n\n"+string(s.Src))
+	}
+	for _, f := range paramsFiles {
+		imp.srcs[f.ImportPath] = append(imp.srcs[f.ImportPath], string(f.Src))
+	}
+	if _, err := imp.check(spec.PackageName, files); err != nil {
+		t.Errorf("type-check of the generated files: %v", err)
+	}
+}
+
+// releaseTag reports whether the running Go satisfies the build tag of the version such as "go1.22".
+func releaseTag(v string) bool {
+	for _, tag := range build.Default.ReleaseTags {
+		if tag == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,1426 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+const stdrouterPkg = "github.com/tetsuzawa/stdrouter"
+
+// generator writes the Go source with the templates.
+type generator struct {
+	buf bytes.Buffer
+	Options
+	// routeNames are the names of the routes by the method and the pattern.
+	routeNames map[string]string
+	// methodNames are the names of the methods of the Handlers interface by the handler functions (Options.Interface).
+	methodNames map[string]string
+}
+
+func (g *generator) Printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// parseTpl parses the template of the name in Options.Templates or the built-in one.
+func (g *generator) parseTpl(name string) (*template.Template, error) {
+	text, ok := g.Templates[name]
+	if !ok {
+		text = builtinTpls[name]
+	}
+	t, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: `%s` -> %w", name, err)
+	}
+	return t, nil
+}
+
+// renderTpl returns the result of the template of the name without the surrounding spaces.
+func (g *generator) renderTpl(name string, data interface{}) (string, error) {
+	t, err := g.parseTpl(name)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: `%s` -> %w", name, err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+func (g *generator) writeTpl(t *template.Template, data interface{}) error {
+	if err := t.Execute(&g.buf, data); err != nil {
+		return fmt.Errorf("failed to execute template -> %w", err)
+	}
+	return nil
+}
+
+// generateHeadMsg generates the header of the generated file.
+// The go:generate directive of Options.Directive is written unless the file is the test, so that go generate runs stdrouter once.
+func (g *generator) generateHeadMsg(directive bool) error {
+	t, err := g.parseTpl("HeadMsg")
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Constraint string
+		Generate   string
+	}{
+		Constraint: g.buildConstraint(),
+	}
+	if directive {
+		data.Generate = g.Directive
+	}
+	return g.writeTpl(t, data)
+}
+
+// buildConstraint returns the Go version constraint required by the options in addition to !stdrouter.
+func (g *generator) buildConstraint() string {
+	v := g.MinGoVersion()
+	if v == "" {
+		return ""
+	}
+	return "go" + v
+}
+
+func (g *generator) generatePackage(name string) error {
+	t, err := g.parseTpl("Package")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, name)
+}
+
+func (g *generator) generateImport() error {
+	t, err := g.parseTpl("Import")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, nil)
+}
+
+func (g *generator) generateImportImpl(spec string) error {
+	t, err := g.parseTpl("ImportSpec")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, spec)
+}
+
+// importedPkgs returns the sorted packages imported by the generated file.
+// The stdrouter package imported in the router file is dropped.
+func (g *generator) importedPkgs(cfg *config, pkgs ...string) []string {
+	pkgs = append(append([]string{}, g.routerImports(cfg)...), pkgs...)
+	pkgs = stdrouter.Drop(stdrouterPkg, pkgs)
+	pkgs = stdrouter.DropDuplication(pkgs)
+	sort.Strings(pkgs)
+	return pkgs
+}
+
+func (g *generator) generateClosingBracket() error {
+	t, err := g.parseTpl("ClosingBracket")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, nil)
+}
+
+func (g *generator) generateRouter(cfg *config) error {
+	t, err := g.parseTpl("Router")
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Name      string
+		Recover   bool
+		Mounts    []Mount
+		Interface bool
+	}{
+		Name:      cfg.RouterInstanceName,
+		Recover:   cfg.PanicHandler != nil,
+		Mounts:    cfg.Mounts,
+		Interface: g.Interface,
+	}
+	return g.writeTpl(t, data)
+}
+
+func (g *generator) generateHandlerFunc(funcName string, pathParams []string) error {
+	t, err := g.parseTpl("HandlerFunc")
+	if err != nil {
+		return err
+	}
+	data := struct {
+		FuncName   string
+		PathParams []string
+		Interface  bool
+	}{
+		FuncName:   funcName,
+		PathParams: pathParams,
+		Interface:  g.Interface,
+	}
+
+	return g.writeTpl(t, data)
+}
+
+func (g *generator) generateSeparatePath(n int) error {
+	t, err := g.parseTpl("SeparatePath")
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Base string
+		Num  int
+		Tail string
+	}{
+		Base: "p",
+		Num:  n,
+		Tail: "p",
+	}
+	return g.writeTpl(t, data)
+}
+
+func (g *generator) generateSeparateParam(n int) error {
+	t, err := g.parseTpl("SeparatePath")
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Base string
+		Num  int
+		Tail string
+	}{
+		Base: "endpoint",
+		Num:  n,
+		Tail: "param",
+	}
+	return g.writeTpl(t, data)
+}
+
+func (g *generator) generateSwitch(target string) error {
+	t, err := g.parseTpl("Switch")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, target)
+}
+
+func (g *generator) generateCasePath(path string) error {
+	t, err := g.parseTpl("Case")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, path)
+}
+
+func (g *generator) generateClosingCurlyBraces() error {
+	t, err := g.parseTpl("ClosingCurlyBraces")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, nil)
+}
+
+func (g *generator) generateCaseMethod(httpMethod string) error {
+	t, err := g.parseTpl("Case")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, methodConst(httpMethod))
+}
+
+func (g *generator) generateFunc(handlerFunc stdrouter.HandlerFunc, args []string) error {
+	t, err := g.parseTpl("Impl")
+	if err != nil {
+		return err
+	}
+	sargs := "(w, r"
+	if g.Interface {
+		sargs = "(h, w, r"
+	}
+	for _, p := range args {
+		sargs = fmt.Sprintf("%s, %s", sargs, p)
+	}
+	sargs += ")"
+
+	if handlerFunc.Package == "" {
+		if err = g.writeTpl(t, handlerFunc.Func+sargs); err != nil {
+			return err
+		}
+		return nil
+	}
+	return g.writeTpl(t, handlerFunc.Package+"."+handlerFunc.Func+sargs)
+}
+
+// handlerCall is the data of the HandlerCall template.
+type handlerCall struct {
+	// Func is the function to be called.
+	Func string
+	// Args are the arguments of the call.
+	Args string
+	// Handler is the handler function as written in the router file.
+	Handler string
+	// Method, Pattern and Name are the route of the handler. They are empty for NotFound and MethodNotAllowed handlers.
+	Method  string
+	Pattern string
+	Name    string
+}
+
+// renderHandlerCall returns the statement to call the handler with w, r and the path params in args.
+func (g *generator) renderHandlerCall(h stdrouter.HandlerFunc, args []string, method, pattern string) (string, error) {
+	req := "r"
+	if g.Params == ParamsContext {
+		// pass the path params in the context of the request
+		if names := (Route{Pattern: pattern}).Params(); len(names) != 0 {
+			var kv []string
+			for i, name := range names {
+				kv = append(kv, strconv.Quote(name), args[i])
+			}
+			req = "withParams(r, " + strings.Join(kv, ", ") + ")"
+		}
+		args = nil
+	}
+	if g.Params == ParamsStruct {
+		// pass the path params in the struct
+		if names := (Route{Pattern: pattern}).Params(); len(names) != 0 {
+			var fields []string
+			for i, name := range names {
+				fields = append(fields, stdrouter.SnakeToGoName(name)+": "+args[i])
+			}
+			args = []string{paramsType(h) + "{" + strings.Join(fields, ", ") + "}"}
+		}
+	}
+	callee := h
+	if g.Interface && method != "" {
+		// the handlers of the routes are the methods of the Handlers passed to NewRouter
+		callee = stdrouter.HandlerFunc{Package: "h", Func: g.methodNames[handlerName(h)]}
+	}
+	return g.renderTpl("HandlerCall", handlerCall{
+		Func:    handlerName(callee),
+		Args:    strings.Join(append([]string{"w", req}, args...), ", "),
+		Handler: handlerName(h),
+		Method:  method,
+		Pattern: pattern,
+		Name:    g.routeNames[method+" "+pattern],
+	})
+}
+
+// routeContext reports whether the router stores the matched route in the context of the request,
+// which the generated test reads with Options.Testable.
+func (g *generator) routeContext() bool {
+	return g.RouteContext || g.Testable
+}
+
+// withRouteStmts returns the statement to store the route in the context of the request with RouteContext.
+func (g *generator) withRouteStmts(method, pattern string) []string {
+	if !g.routeContext() || method == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("r = withRoute(r, %q, %q)", g.routeNames[method+" "+pattern], pattern)}
+}
+
+// routeNames returns the names of the routes by the method and the pattern.
+func routeNames(cfg *config) map[string]string {
+	names := make(map[string]string)
+	for _, rt := range cfg.Routes {
+		names[rt.Method+" "+rt.Pattern] = rt.Name
+	}
+	return names
+}
+
+// Buckets of the metrics for the requests not dispatched to the routes.
+const (
+	bucketNotFound         = "NotFound"
+	bucketMethodNotAllowed = "MethodNotAllowed"
+	// bucketMount followed by the prefix counts the requests passed to the handler mounted to the prefix.
+	bucketMount = "Mount"
+)
+
+// metricsStmts returns the statements to record the request to the metrics of the key with Options.Metrics.
+func (g *generator) metricsStmts(key string) []string {
+	if !g.Metrics {
+		return nil
+	}
+	return []string{fmt.Sprintf("w, done := track(%q, w)", key), "defer done()"}
+}
+
+// generateErrorHandlerCall generates the call of the NotFound or MethodNotAllowed handler counted in the bucket.
+func (g *generator) generateErrorHandlerCall(h stdrouter.HandlerFunc, bucket string) error {
+	for _, stmt := range g.metricsStmts(bucket) {
+		g.Printf("%s\n", stmt)
+	}
+	return g.generateHandlerCall(h, nil, "", "")
+}
+
+// notFoundCall returns the statement to call the NotFound handler.
+// The handler of the group is chosen at runtime by handleNotFound if the groups declare NotFound handlers.
+func (g *generator) notFoundCall(cfg *config) (string, error) {
+	if len(cfg.ScopedNotFound) == 0 {
+		return g.renderHandlerCall(*cfg.NotFoundHandler, nil, "", "")
+	}
+	return "handleNotFound(w, r)", nil
+}
+
+// allowStmts returns the statement to set the Allow header to the methods of the node
+// if the built-in MethodNotAllowed handler is called for it.
+func (g *generator) allowStmts(cfg *config, node *stdrouter.Node) []string {
+	if cfg.methodNotAllowedFor(stdrouter.BuildPath(node)) != (stdrouter.HandlerFunc{Func: defaultMethodNotAllowed}) {
+		return nil
+	}
+	var methods []string
+	_, get := node.Methods[http.MethodGet]
+	for _, m := range Methods {
+		_, ok := node.Methods[m]
+		// ServeMux dispatches HEAD requests to the GET handler
+		if ok || (m == http.MethodHead && get && g.Target == TargetServeMux) {
+			methods = append(methods, m)
+		}
+	}
+	return []string{fmt.Sprintf("w.Header().Set(\"Allow\", %q)", strings.Join(methods, ", "))}
+}
+
+// generateMethodNotAllowedCall generates the call of the MethodNotAllowed handler of the group for the node.
+func (g *generator) generateMethodNotAllowedCall(cfg *config, node *stdrouter.Node) error {
+	for _, stmt := range g.allowStmts(cfg, node) {
+		g.Printf("%s\n", stmt)
+	}
+	return g.generateErrorHandlerCall(cfg.methodNotAllowedFor(stdrouter.BuildPath(node)), bucketMethodNotAllowed)
+}
+
+// generateNotFoundCall generates the call of the NotFound handler counted in the NotFound bucket.
+func (g *generator) generateNotFoundCall(cfg *config) error {
+	for _, stmt := range g.metricsStmts(bucketNotFound) {
+		g.Printf("%s\n", stmt)
+	}
+	call, err := g.notFoundCall(cfg)
+	if err != nil {
+		return err
+	}
+	g.Printf("%s\n", call)
+	return nil
+}
+
+func (g *generator) generateHandlerCall(h stdrouter.HandlerFunc, args []string, method, pattern string) error {
+	if method != "" {
+		for _, stmt := range g.metricsStmts(method + " " + pattern) {
+			g.Printf("%s\n", stmt)
+		}
+	}
+	for _, stmt := range g.withRouteStmts(method, pattern) {
+		g.Printf("%s\n", stmt)
+	}
+	if g.PathValue {
+		for i, name := range (Route{Pattern: pattern}).Params() {
+			g.Printf("r.SetPathValue(%q, %s)\n", name, args[i])
+		}
+	}
+	call, err := g.renderHandlerCall(h, args, method, pattern)
+	if err != nil {
+		return err
+	}
+	g.Printf("%s\n", call)
+	return nil
+}
+
+func (g *generator) generateDefault() error {
+	t, err := g.parseTpl("Default")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, nil)
+}
+
+func (g *generator) generateIf(expr string) error {
+	t, err := g.parseTpl("If")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, expr)
+}
+
+func (g *generator) generateElse() error {
+	t, err := g.parseTpl("Else")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, nil)
+}
+
+// helperPkgs returns the packages used in the helper functions for the options and the router file.
+func (g *generator) helperPkgs(cfg *config) []string {
+	var pkgs []string
+	if g.Params == ParamsContext {
+		pkgs = append(pkgs, "context")
+	}
+	if cfg.PanicHandler != nil {
+		pkgs = append(pkgs, "log", "runtime/debug")
+	}
+	if g.RouteTable {
+		pkgs = append(pkgs, "path", "strings")
+	}
+	if g.routeContext() {
+		pkgs = append(pkgs, "context")
+	}
+	if g.Metrics {
+		pkgs = append(pkgs, "bufio", "errors", "expvar", "net", "strconv", "time")
+	}
+	if len(cfg.Mounts) != 0 {
+		pkgs = append(pkgs, "net/url", "strings")
+	}
+	if len(cfg.ScopedNotFound) != 0 {
+		pkgs = append(pkgs, "path", "strings")
+	}
+	return pkgs
+}
+
+// generateHelperFuncs generates the helper functions for the options and the router file.
+func (g *generator) generateHelperFuncs(cfg *config) error {
+	if cfg.PanicHandler != nil {
+		// the panic handler is called directly, since it is not a route to be tested
+		call, err := g.renderTpl("HandlerCall", handlerCall{
+			Func:    handlerName(*cfg.PanicHandler),
+			Args:    "w, r, v",
+			Handler: handlerName(*cfg.PanicHandler),
+		})
+		if err != nil {
+			return err
+		}
+		t, err := g.parseTpl("RecoverFunc")
+		if err != nil {
+			return err
+		}
+		if err = g.writeTpl(t, call); err != nil {
+			return err
+		}
+	}
+	if g.RouteTable {
+		t, err := g.parseTpl("RouteTable")
+		if err != nil {
+			return err
+		}
+		if err = g.writeTpl(t, collectRouteInfos(cfg)); err != nil {
+			return err
+		}
+	}
+	if len(cfg.Mounts) != 0 {
+		t, err := g.parseTpl("MountFunc")
+		if err != nil {
+			return err
+		}
+		if err = g.writeTpl(t, struct{ Metrics bool }{g.Metrics}); err != nil {
+			return err
+		}
+	}
+	if cfg.DefaultNotFound || cfg.DefaultMethodNotAllowed {
+		if err := g.generateDefaultHandlers(cfg); err != nil {
+			return fmt.Errorf("generateDefaultHandlers -> %w", err)
+		}
+	}
+	if len(cfg.ScopedNotFound) != 0 {
+		if err := g.generateNotFoundFunc(cfg); err != nil {
+			return fmt.Errorf("generateNotFoundFunc -> %w", err)
+		}
+	}
+	if g.Metrics {
+		var keys []string
+		for _, rt := range collectRoutes(cfg.Node) {
+			keys = append(keys, rt.Method+" "+stdrouter.BuildPath(rt.Node))
+		}
+		keys = append(keys, bucketNotFound, bucketMethodNotAllowed)
+		for _, m := range cfg.Mounts {
+			keys = append(keys, bucketMount+" "+m.Prefix)
+		}
+		name := g.MetricsName
+		if name == "" {
+			name = cfg.PackageName
+		}
+		t, err := g.parseTpl("MetricsFunc")
+		if err != nil {
+			return err
+		}
+		if err = g.writeTpl(t, struct {
+			Name string
+			Keys []string
+		}{name, keys}); err != nil {
+			return err
+		}
+	}
+	if g.routeContext() {
+		t, err := g.parseTpl("RouteFunc")
+		if err != nil {
+			return err
+		}
+		if err = g.writeTpl(t, nil); err != nil {
+			return err
+		}
+	}
+	if g.Params != ParamsContext {
+		return nil
+	}
+	t, err := g.parseTpl("ParamsFunc")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, nil)
+}
+
+// defaultHandler is the built-in NotFound or MethodNotAllowed handler.
+type defaultHandler struct {
+	// Name is the name of the function.
+	Name string
+	// Declaration is the method of the router file declaring the handler instead, such as "HandleNotFound".
+	Declaration string
+	// Code is the constant of the status code such as "http.StatusNotFound", and Status is the status line.
+	Code   string
+	Status string
+	// ContentType is the media type of the Body. It is empty for the plain text written with http.Error.
+	ContentType string
+	Body        string
+}
+
+// newDefaultHandler returns the built-in handler replying with the status code in the error format.
+func newDefaultHandler(name, declaration, code string, status int, format string) defaultHandler {
+	h := defaultHandler{
+		Name:        name,
+		Declaration: declaration,
+		Code:        code,
+		Status:      strconv.Itoa(status) + " " + http.StatusText(status),
+	}
+	switch format {
+	case ErrorFormatJSON:
+		h.ContentType = "application/json"
+		h.Body = fmt.Sprintf(`{"error":%q}`+"\n", http.StatusText(status))
+	case ErrorFormatProblem:
+		h.ContentType = "application/problem+json"
+		h.Body = fmt.Sprintf(`{"type":"about:blank","title":%q,"status":%d}`+"\n", http.StatusText(status), status)
+	default:
+		// the same as http.NotFound and net/http.ServeMux
+		h.Body = http.StatusText(status)
+		if status == http.StatusNotFound {
+			h.Body = "404 page not found"
+		}
+	}
+	return h
+}
+
+// generateDefaultHandlers generates the built-in handlers for the NotFound and MethodNotAllowed handlers not declared.
+func (g *generator) generateDefaultHandlers(cfg *config) error {
+	var handlers []defaultHandler
+	if cfg.DefaultNotFound {
+		handlers = append(handlers, newDefaultHandler(defaultNotFound, "HandleNotFound", "http.StatusNotFound", http.StatusNotFound, g.ErrorFormat))
+	}
+	if cfg.DefaultMethodNotAllowed {
+		handlers = append(handlers, newDefaultHandler(defaultMethodNotAllowed, "HandleMethodNotAllowed", "http.StatusMethodNotAllowed", http.StatusMethodNotAllowed, g.ErrorFormat))
+	}
+	t, err := g.parseTpl("DefaultHandlers")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, handlers)
+}
+
+// scopedCall is the prefix of the group and the statement calling the handler declared in it.
+type scopedCall struct {
+	Prefix string
+	Call   string
+}
+
+// generateNotFoundFunc generates handleNotFound, which calls the NotFound handler of the group nearest to the path.
+func (g *generator) generateNotFoundFunc(cfg *config) error {
+	var scopes []scopedCall
+	for _, sh := range cfg.ScopedNotFound {
+		call, err := g.renderHandlerCall(sh.Handler, nil, "", "")
+		if err != nil {
+			return err
+		}
+		scopes = append(scopes, scopedCall{Prefix: sh.Prefix, Call: call})
+	}
+	def, err := g.renderHandlerCall(*cfg.NotFoundHandler, nil, "", "")
+	if err != nil {
+		return err
+	}
+	t, err := g.parseTpl("NotFoundFunc")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, struct {
+		Scopes  []scopedCall
+		Default string
+	}{Scopes: scopes, Default: def})
+}
+
+// routeInfo is the element of the table of the routes in the generated code.
+type routeInfo struct {
+	Name    string
+	Method  string
+	Pattern string
+	Handler string
+	Params  []string
+}
+
+// collectRouteInfos returns the routes in the order of the router file.
+// The route registered again with the same method and pattern is replaced by the later one as in the router.
+func collectRouteInfos(cfg *config) []routeInfo {
+	last := map[string]int{}
+	for i, rt := range cfg.Routes {
+		last[rt.Method+" "+rt.Pattern] = i
+	}
+	var infos []routeInfo
+	for i, rt := range cfg.Routes {
+		if last[rt.Method+" "+rt.Pattern] != i {
+			continue
+		}
+		infos = append(infos, routeInfo{
+			Name:    rt.Name,
+			Method:  rt.Method,
+			Pattern: rt.Pattern,
+			Handler: rt.Handler.String(),
+			Params:  rt.Params(),
+		})
+	}
+	return infos
+}
+
+// paramsStruct is the struct of the path params passed to the handler with ParamsStruct.
+type paramsStruct struct {
+	// Package is the package of the handler, where the struct is declared.
+	Package string
+	Name    string
+	Handler string
+	Fields  []paramsField
+}
+
+// paramsField is the field of the struct for the path param.
+type paramsField struct {
+	Name  string
+	Param string
+}
+
+// paramsType returns the qualified name of the struct of the path params passed to the handler.
+func paramsType(h stdrouter.HandlerFunc) string {
+	return handlerName(stdrouter.HandlerFunc{Package: h.Package, Func: h.Func + "Params"})
+}
+
+// collectParamsStructs returns the structs of the path params of the handlers in the order of the routes.
+// The handler registered to the routes with different path params is reported as an error.
+func collectParamsStructs(cfg *config) ([]paramsStruct, error) {
+	var structs []paramsStruct
+	registered := map[string][]string{}
+	for _, rt := range collectRoutes(cfg.Node) {
+		params := stdrouter.PathParams(rt.Node)
+		name := handlerName(rt.Handler)
+		if prev, ok := registered[name]; ok {
+			if strings.Join(prev, "/") != strings.Join(params, "/") {
+				return nil, fmt.Errorf("%s is registered to the routes with different path params: [%s] and [%s]",
+					name, strings.Join(prev, ", "), strings.Join(params, ", "))
+			}
+			continue
+		}
+		registered[name] = params
+		if len(params) == 0 {
+			continue
+		}
+		s := paramsStruct{Package: rt.Handler.Package, Name: rt.Handler.Func + "Params", Handler: rt.Handler.Func}
+		for _, p := range params {
+			s.Fields = append(s.Fields, paramsField{Name: stdrouter.SnakeToGoName(p), Param: p})
+		}
+		structs = append(structs, s)
+	}
+	return structs, nil
+}
+
+// paramsPkgs returns the sorted packages where the structs are declared.
+func paramsPkgs(structs []paramsStruct) []string {
+	var pkgs []string
+	for _, s := range structs {
+		pkgs = append(pkgs, s.Package)
+	}
+	pkgs = stdrouter.DropDuplication(pkgs)
+	sort.Strings(pkgs)
+	return pkgs
+}
+
+// findImport returns the import path of the package qualified by the name.
+func findImport(imports []string, names map[string]string, pkg string) (string, bool) {
+	for _, p := range imports {
+		if importName(names, p) == pkg {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+// importName returns the name qualifying the imported package in the router file.
+func importName(names map[string]string, p string) string {
+	if name, ok := names[p]; ok {
+		return name
+	}
+	return path.Base(p)
+}
+
+// importSpec returns the import spec of the package, which is named if the name differs from the last element of the path.
+func importSpec(names map[string]string, p string) string {
+	if name := importName(names, p); name != path.Base(p) {
+		return name + " " + strconv.Quote(p)
+	}
+	return strconv.Quote(p)
+}
+
+func (g *generator) writeParamsStructs(structs []paramsStruct, pkg string) error {
+	var data []paramsStruct
+	for _, s := range structs {
+		if s.Package == pkg {
+			data = append(data, s)
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	t, err := g.parseTpl("ParamsStruct")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, data)
+}
+
+// generateParamsStructs generates the structs of the path params of the handlers in the package of the router file.
+func (g *generator) generateParamsStructs(cfg *config) error {
+	if g.Params != ParamsStruct {
+		return nil
+	}
+	structs, err := collectParamsStructs(cfg)
+	if err != nil {
+		return fmt.Errorf("collectParamsStructs -> %w", err)
+	}
+	return g.writeParamsStructs(structs, "")
+}
+
+// generateParamsFile generates the file declaring the structs of the path params in the package of the handlers.
+func (g *generator) generateParamsFile(structs []paramsStruct, pkg string) error {
+	t, err := g.parseTpl("ParamsFile")
+	if err != nil {
+		return err
+	}
+	if err = g.writeTpl(t, pkg); err != nil {
+		return err
+	}
+	return g.writeParamsStructs(structs, pkg)
+}
+
+func (g *generator) generateSeparatePathFunc() error {
+	t, err := g.parseTpl("SeparatePathFunc")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, nil)
+}
+
+// format returns the formatted source. If the source is invalid, it returns the unformatted one with the error.
+func (g *generator) format() ([]byte, error) {
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return g.buf.Bytes(), fmt.Errorf("invalid Go generated: %w", err)
+	}
+	return src, nil
+}
+
+func (g *generator) generate(cfg *config) error {
+	g.routeNames = routeNames(cfg)
+	var methods []handlersMethod
+	if g.Interface {
+		var err error
+		if methods, g.methodNames, err = g.collectHandlersMethods(cfg); err != nil {
+			return fmt.Errorf("collectHandlersMethods -> %w", err)
+		}
+	}
+	switch g.Target {
+	case "", TargetRouter:
+	case TargetServeMux:
+		return g.generateServeMux(cfg, methods)
+	default:
+		return fmt.Errorf("unknown target: %s", g.Target)
+	}
+	var err error
+
+	// generate headers
+	if err = g.generateHeadMsg(true); err != nil {
+		return fmt.Errorf("generateHeadMsg -> %w", err)
+	}
+	if err = g.generatePackage(cfg.PackageName); err != nil {
+		return fmt.Errorf("generatePackage -> %w", err)
+	}
+	if err = g.generateImport(); err != nil {
+		return fmt.Errorf("generateImport -> %w", err)
+	}
+
+	// "path" and "strings" are used in SeparatePath func
+	for _, v := range g.importedPkgs(cfg, append(g.helperPkgs(cfg), "path", "strings")...) {
+		if err = g.generateImportImpl(importSpec(cfg.ImportNames, v)); err != nil {
+			return fmt.Errorf("generateImportImpl -> %w", err)
+		}
+	}
+	if err = g.generateClosingBracket(); err != nil {
+		return fmt.Errorf("generateClosingBracket -> %w", err)
+	}
+	if g.Interface {
+		if err = g.generateHandlers(methods); err != nil {
+			return fmt.Errorf("generateHandlers -> %w", err)
+		}
+	}
+	if err = g.generateRouter(cfg); err != nil {
+		return fmt.Errorf("generateRouter -> %w", err)
+	}
+
+	// find hierarchy to path parameter and max depth
+	var nodeHierarchies []stdrouter.Node
+	sentinelNode := &stdrouter.Node{Depth: 0}
+	stdrouter.Walk(cfg.Node, func(node *stdrouter.Node) bool {
+		if node.IsPathParam {
+			nodeHierarchies = append(nodeHierarchies, *node)
+		}
+		if node.Depth > sentinelNode.Depth {
+			sentinelNode.Depth = node.Depth
+		}
+		// the path param without children separates its own endpoint "/" from the paths not found
+		if node.IsPathParam && node.Depth+1 > sentinelNode.Depth {
+			sentinelNode.Depth = node.Depth + 1
+		}
+		return true
+	})
+	nodeHierarchies = append(nodeHierarchies, *sentinelNode)
+
+	// generate functions
+	handlerFuncName := "Base"
+	var pathParams []string
+	var startingNode = cfg.Node
+	for i, pathParamBaseNode := range nodeHierarchies {
+		if err = g.generateHandlerFunc("handle"+handlerFuncName, pathParams); err != nil {
+			return fmt.Errorf("generateHandlerFunc -> %w", err)
+		}
+		if i == 0 {
+			if err = g.generateSeparatePath(pathParamBaseNode.Depth); err != nil {
+				return fmt.Errorf("generateSeparatePath -> %w", err)
+			}
+		} else {
+			if err = g.generateSeparatePath(pathParamBaseNode.Depth - nodeHierarchies[i-1].Depth); err != nil {
+				return fmt.Errorf("generateSeparatePath -> %w", err)
+			}
+		}
+
+		if err = g.generateSwitch("endpoint"); err != nil {
+			return fmt.Errorf("generateSwitch -> %w", err)
+		}
+
+		var pathParamHandler *stdrouter.HandlerFunc
+		// generate switches of router
+		stdrouter.Walk(startingNode, func(node *stdrouter.Node) bool {
+			if node.Depth > pathParamBaseNode.Depth {
+				return false
+			}
+			p := "/"
+			if node != startingNode {
+				p = path.Clean(stdrouter.BuildBasePath(node) + path.Clean("/"+node.Endpoint))
+			}
+			if pathParamBaseNode.IsPathParam && node.Endpoint == pathParamBaseNode.Endpoint {
+				pathParamHandler = &stdrouter.HandlerFunc{
+					Func: "handle" + stdrouter.SnakeToCamel(pathParamBaseNode.Endpoint),
+				}
+				// to generate the function with name "handle<Param>"
+				handlerFuncName = stdrouter.SnakeToCamel(node.Endpoint)
+				startingNode = node
+				pathParams = append(pathParams, stdrouter.ToLowerFirstLetter(stdrouter.SnakeToCamel(node.Endpoint)))
+				return true
+			}
+
+			if err = g.generateCasePath(strconv.Quote(p)); err != nil {
+				err = fmt.Errorf("generateCasePath -> %w", err)
+				return false
+			}
+			// the endpoint only leading to the routes is not found as the paths not declared
+			if len(node.Methods) == 0 {
+				if err = g.generateNotFoundCall(cfg); err != nil {
+					err = fmt.Errorf("generateNotFoundCall -> %w", err)
+					return false
+				}
+				g.Printf("\n")
+				return true
+			}
+			if err = g.generateSwitch("r.Method"); err != nil {
+				err = fmt.Errorf("generateSwitch -> %w", err)
+				return false
+			}
+			for _, httpMethod := range sortedMethods(node) {
+				handlerFunc := node.Methods[httpMethod]
+				if err = g.generateCaseMethod(httpMethod); err != nil {
+					err = fmt.Errorf("generateCaseMethod -> %w", err)
+					return false
+				}
+				if err = g.generateHandlerCall(handlerFunc, pathParams, httpMethod, stdrouter.BuildPath(node)); err != nil {
+					err = fmt.Errorf("generateHandlerCall -> %w", err)
+					return false
+				}
+			}
+			if err = g.generateDefault(); err != nil {
+				err = fmt.Errorf("generateDefault -> %w", err)
+				return false
+			}
+			if err = g.generateMethodNotAllowedCall(cfg, node); err != nil {
+				err = fmt.Errorf("generateMethodNotAllowedCall -> %w", err)
+				return false
+			}
+			if err = g.generateClosingCurlyBraces(); err != nil {
+				err = fmt.Errorf("generateClosingCurlyBraces -> %w", err)
+				return false
+			}
+			return true
+		})
+		if err != nil {
+			return fmt.Errorf("stdrouter.Walk -> %w", err)
+		}
+
+		if err = g.generateDefault(); err != nil {
+			return fmt.Errorf("generateDefault -> %w", err)
+		}
+		if pathParamHandler != nil {
+			if i == 0 {
+				if err = g.generateSeparateParam(pathParamBaseNode.Depth - 1); err != nil {
+					return fmt.Errorf("generateSeparateParam -> %w", err)
+				}
+			} else {
+				if err = g.generateSeparateParam(pathParamBaseNode.Depth - nodeHierarchies[i-1].Depth - 1); err != nil {
+					return fmt.Errorf("generateSeparateParam -> %w", err)
+				}
+			}
+			if err = g.generateIf(fmt.Sprintf(`endpoint == "%s"`, stdrouter.BuildBasePath(&pathParamBaseNode))); err != nil {
+				return fmt.Errorf("generateIf -> %w", err)
+			}
+			args := append([]string{"p"}, pathParams[:len(pathParams)-1]...)
+			args = append(args, "param[1:]")
+			if err = g.generateFunc(*pathParamHandler, args); err != nil {
+				return fmt.Errorf("generateFunc -> %w", err)
+			}
+			if err = g.generateElse(); err != nil {
+				return fmt.Errorf("generateElse -> %w", err)
+			}
+			if err = g.generateNotFoundCall(cfg); err != nil {
+				return fmt.Errorf("generateNotFoundCall -> %w", err)
+			}
+			if err = g.generateClosingCurlyBraces(); err != nil {
+				return fmt.Errorf("generateClosingCurlyBraces -> %w", err)
+			}
+		} else {
+			if err = g.generateNotFoundCall(cfg); err != nil {
+				return fmt.Errorf("generateNotFoundCall -> %w", err)
+			}
+		}
+		// end switch
+		if err = g.generateClosingCurlyBraces(); err != nil {
+			return fmt.Errorf("generateClosingCurlyBraces -> %w", err)
+		}
+		// end func
+		if err = g.generateClosingCurlyBraces(); err != nil {
+			return fmt.Errorf("generateClosingCurlyBraces -> %w", err)
+		}
+	}
+
+	// generate helper func
+	if err = g.generateSeparatePathFunc(); err != nil {
+		return fmt.Errorf("generateSeparatePathFunc -> %w", err)
+	}
+	if err = g.generateHelperFuncs(cfg); err != nil {
+		return fmt.Errorf("generateHelperFuncs -> %w", err)
+	}
+	if err = g.generateParamsStructs(cfg); err != nil {
+		return fmt.Errorf("generateParamsStructs -> %w", err)
+	}
+
+	return nil
+}
+
+// route is a pair of the HTTP method and the handler registered to the node.
+type route struct {
+	Method  string
+	Node    *stdrouter.Node
+	Handler stdrouter.HandlerFunc
+}
+
+// param is a parameter of the function.
+type param struct {
+	Name string
+	Type string
+}
+
+// testCase is a request sent by the generated test and the route expected to match it.
+type testCase struct {
+	Method  string
+	Path    string
+	Pattern string
+	// Fallback is the NotFound or MethodNotAllowed handler expected to respond if no route matches the request.
+	Fallback string
+}
+
+// sortedMethods returns the HTTP methods registered to the node in the order of Methods.
+func sortedMethods(node *stdrouter.Node) []string {
+	var methods []string
+	for _, m := range Methods {
+		if _, ok := node.Methods[m]; ok {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+// collectRoutes returns every route registered to the tree in the order of stdrouter.Walk.
+func collectRoutes(root *stdrouter.Node) []route {
+	var routes []route
+	stdrouter.Walk(root, func(node *stdrouter.Node) bool {
+		for _, m := range sortedMethods(node) {
+			routes = append(routes, route{Method: m, Node: node, Handler: node.Methods[m]})
+		}
+		return true
+	})
+	return routes
+}
+
+// handlerName returns the qualified name of the handler function as written in the router file.
+func handlerName(h stdrouter.HandlerFunc) string {
+	if h.Package == "" {
+		return h.Func
+	}
+	return h.Package + "." + h.Func
+}
+
+// handlerParams returns the parameters of the handler of the route following w and r.
+func (g *generator) handlerParams(rt route) []param {
+	pathParams := stdrouter.PathParams(rt.Node)
+	if g.Params == ParamsStruct && len(pathParams) != 0 {
+		return []param{{Name: "params", Type: paramsType(rt.Handler)}}
+	}
+	if g.Params != ParamsPositional && g.Params != "" {
+		return nil
+	}
+	var params []param
+	for _, p := range pathParams {
+		params = append(params, param{Name: stdrouter.ToLowerFirstLetter(stdrouter.SnakeToCamel(p)), Type: "string"})
+	}
+	return params
+}
+
+// samplePath returns the path to the node with the sample values filled in the path params.
+func samplePath(node *stdrouter.Node) string {
+	p := stdrouter.BuildPath(node)
+	for i, param := range stdrouter.PathParams(node) {
+		p = strings.Replace(p, ":"+param, fmt.Sprintf("%s-%d", param, i+1), 1)
+	}
+	return p
+}
+
+func collectTestCases(cfg *config) []testCase {
+	var cases []testCase
+	for _, rt := range collectRoutes(cfg.Node) {
+		p := samplePath(rt.Node)
+		cases = append(cases, testCase{Method: methodConst(rt.Method), Path: p, Pattern: stdrouter.BuildPath(rt.Node)})
+	}
+
+	// request with the method not registered to the endpoint
+	stdrouter.Walk(cfg.Node, func(node *stdrouter.Node) bool {
+		if len(node.Methods) == 0 {
+			return true
+		}
+		for _, m := range Methods {
+			if _, ok := node.Methods[m]; ok {
+				continue
+			}
+			// ServeMux dispatches HEAD requests to the GET handler
+			if m == http.MethodHead {
+				continue
+			}
+			p := samplePath(node)
+			mna := handlerName(cfg.methodNotAllowedFor(stdrouter.BuildPath(node)))
+			cases = append(cases, testCase{Method: methodConst(m), Path: p, Fallback: mna})
+			break
+		}
+		return true
+	})
+
+	// request to the path not registered in each group, unless a path param captures it
+	for _, sh := range cfg.ScopedNotFound {
+		p := samplePrefix(sh.Prefix) + "/stdrouter-not-found"
+		if capturedByParam(cfg.Node, p) {
+			continue
+		}
+		cases = append(cases, testCase{Method: methodConst(http.MethodGet), Path: p, Fallback: handlerName(sh.Handler)})
+	}
+	if capturedByParam(cfg.Node, "/stdrouter-not-found") {
+		return cases
+	}
+	cases = append(cases, testCase{
+		Method:   methodConst(http.MethodGet),
+		Path:     "/stdrouter-not-found",
+		Fallback: handlerName(*cfg.NotFoundHandler),
+	})
+	return cases
+}
+
+// samplePrefix returns the prefix of the group with the sample values filled in the path params.
+func samplePrefix(prefix string) string {
+	segments := strings.Split(prefix, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = s[1:] + "-1"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// capturedByParam reports whether the last segment of the path p is captured by a path param,
+// in which case the request is not passed to the NotFound handler.
+func capturedByParam(root *stdrouter.Node, p string) bool {
+	captured := false
+	stdrouter.Walk(root, func(node *stdrouter.Node) bool {
+		if node.IsPathParam {
+			pattern := stdrouter.BuildPath(node)
+			if strings.Count(pattern, "/") == strings.Count(p, "/") && underPrefix(p, pattern) {
+				captured = true
+			}
+		}
+		return !captured
+	})
+	return captured
+}
+
+// methodConst returns the constant of the http package for the HTTP method.
+func methodConst(httpMethod string) string {
+	return "http.Method" + strings.Title(strings.ToLower(httpMethod))
+}
+
+func (g *generator) generateTest(cfg *config) error {
+	if err := g.generateHeadMsg(false); err != nil {
+		return fmt.Errorf("generateHeadMsg -> %w", err)
+	}
+	t, err := g.parseTpl("RouterTest")
+	if err != nil {
+		return err
+	}
+	cases := collectTestCases(cfg)
+	// the packages of the NotFound and MethodNotAllowed handlers called by the test
+	var imports []string
+	encountered := map[string]bool{}
+	for _, c := range cases {
+		pkg := strings.Split(c.Fallback, ".")[0]
+		if !strings.Contains(c.Fallback, ".") || encountered[pkg] {
+			continue
+		}
+		encountered[pkg] = true
+		importPath, ok := findImport(cfg.ImportedPkgs, cfg.ImportNames, pkg)
+		if !ok {
+			return fmt.Errorf("import of the package %s is not found", pkg)
+		}
+		imports = append(imports, importSpec(cfg.ImportNames, importPath))
+	}
+	data := struct {
+		PackageName string
+		Imports     []string
+		Cases       []testCase
+	}{
+		PackageName: cfg.PackageName,
+		Imports:     imports,
+		Cases:       cases,
+	}
+	return g.writeTpl(t, data)
+}
+
+// muxRoute is a pattern registered to net/http.ServeMux and the handler call for it.
+type muxRoute struct {
+	Pattern string
+	Call    string
+}
+
+// muxNotAllowed is the MethodNotAllowed handler of a group and the patterns registered with it.
+type muxNotAllowed struct {
+	Call     string
+	Patterns []string
+}
+
+// muxPath converts the route pattern of the router file to the path of the ServeMux pattern.
+// "/api/users/:user_id" is converted to "/api/users/{user_id}" and "/" to "/{$}".
+func muxPath(p string) string {
+	if p == "/" {
+		return "/{$}"
+	}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// muxCall returns the statement to call the handler from the function registered to ServeMux.
+func (g *generator) muxCall(h stdrouter.HandlerFunc, params []string, method, pattern string) (string, error) {
+	var args []string
+	for _, p := range params {
+		args = append(args, fmt.Sprintf("r.PathValue(%q)", p))
+	}
+	call, err := g.renderHandlerCall(h, args, method, pattern)
+	if err != nil {
+		return "", err
+	}
+	// ServeMux sets the path values by itself
+	var stmts []string
+	if method != "" {
+		stmts = g.metricsStmts(method + " " + pattern)
+	}
+	stmts = append(stmts, g.withRouteStmts(method, pattern)...)
+	return strings.Join(append(stmts, call), "\n"), nil
+}
+
+// muxErrorCall returns the statement to call the NotFound or MethodNotAllowed handler counted in the bucket.
+func (g *generator) muxErrorCall(h stdrouter.HandlerFunc, bucket string) (string, error) {
+	call, err := g.muxCall(h, nil, "", "")
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append(g.metricsStmts(bucket), call), "\n"), nil
+}
+
+// generateServeMux generates NewRouter which registers every route to net/http.ServeMux.
+// The requests not matched to any route are passed to the NotFound handler with "/".
+// The methods are the methods of the Handlers interface with Options.Interface.
+func (g *generator) generateServeMux(cfg *config, methods []handlersMethod) error {
+	var err error
+	if err = g.generateHeadMsg(true); err != nil {
+		return fmt.Errorf("generateHeadMsg -> %w", err)
+	}
+	if err = g.generatePackage(cfg.PackageName); err != nil {
+		return fmt.Errorf("generatePackage -> %w", err)
+	}
+	if err = g.generateImport(); err != nil {
+		return fmt.Errorf("generateImport -> %w", err)
+	}
+	for _, v := range g.importedPkgs(cfg, g.helperPkgs(cfg)...) {
+		if err = g.generateImportImpl(importSpec(cfg.ImportNames, v)); err != nil {
+			return fmt.Errorf("generateImportImpl -> %w", err)
+		}
+	}
+	if err = g.generateClosingBracket(); err != nil {
+		return fmt.Errorf("generateClosingBracket -> %w", err)
+	}
+	if g.Interface {
+		if err = g.generateHandlers(methods); err != nil {
+			return fmt.Errorf("generateHandlers -> %w", err)
+		}
+	}
+
+	var routes []muxRoute
+	for _, rt := range collectRoutes(cfg.Node) {
+		pattern := stdrouter.BuildPath(rt.Node)
+		call, err := g.muxCall(rt.Handler, stdrouter.PathParams(rt.Node), rt.Method, pattern)
+		if err != nil {
+			return fmt.Errorf("muxCall -> %w", err)
+		}
+		routes = append(routes, muxRoute{
+			Pattern: strings.ToUpper(rt.Method) + " " + muxPath(pattern),
+			Call:    call,
+		})
+	}
+	methodNotAllowed, err := g.muxErrorCall(*cfg.MethodNotAllowedHandler, bucketMethodNotAllowed)
+	if err != nil {
+		return fmt.Errorf("muxErrorCall -> %w", err)
+	}
+	notFound, err := g.notFoundCall(cfg)
+	if err != nil {
+		return fmt.Errorf("notFoundCall -> %w", err)
+	}
+	notFound = strings.Join(append(g.metricsStmts(bucketNotFound), notFound), "\n")
+	// ServeMux reports conflicts between the pattern without method and the patterns with wildcards,
+	// so the MethodNotAllowed handler is registered with each method not registered to the endpoint.
+	// HEAD is left to the GET pattern, which also matches HEAD requests.
+	// The endpoints in the groups declaring MethodNotAllowed handlers are registered with them,
+	// and the endpoints passed to the built-in handler are registered one by one to set the Allow header.
+	var notAllowedPatterns []string
+	scopedPatterns := make([][]string, len(cfg.ScopedMethodNotAllowed))
+	var allowNotAllowed []muxNotAllowed
+	stdrouter.Walk(cfg.Node, func(node *stdrouter.Node) bool {
+		if len(node.Methods) == 0 || err != nil {
+			return err == nil
+		}
+		var patterns []string
+		for _, m := range Methods {
+			if _, ok := node.Methods[m]; !ok && m != http.MethodHead {
+				patterns = append(patterns, strings.ToUpper(m)+" "+muxPath(stdrouter.BuildPath(node)))
+			}
+		}
+		if allow := g.allowStmts(cfg, node); allow != nil {
+			var call string
+			call, err = g.muxErrorCall(*cfg.MethodNotAllowedHandler, bucketMethodNotAllowed)
+			allowNotAllowed = append(allowNotAllowed, muxNotAllowed{Call: strings.Join(append(allow, call), "\n"), Patterns: patterns})
+			return err == nil
+		}
+		for i, sh := range cfg.ScopedMethodNotAllowed {
+			if underPrefix(stdrouter.BuildPath(node), sh.Prefix) {
+				scopedPatterns[i] = append(scopedPatterns[i], patterns...)
+				return true
+			}
+		}
+		notAllowedPatterns = append(notAllowedPatterns, patterns...)
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("muxErrorCall -> %w", err)
+	}
+	var scopedNotAllowed []muxNotAllowed
+	for i, sh := range cfg.ScopedMethodNotAllowed {
+		if len(scopedPatterns[i]) == 0 {
+			continue
+		}
+		call, err := g.muxErrorCall(sh.Handler, bucketMethodNotAllowed)
+		if err != nil {
+			return fmt.Errorf("muxErrorCall -> %w", err)
+		}
+		scopedNotAllowed = append(scopedNotAllowed, muxNotAllowed{Call: call, Patterns: scopedPatterns[i]})
+	}
+	scopedNotAllowed = append(scopedNotAllowed, allowNotAllowed...)
+
+	t, err := g.parseTpl("ServeMux")
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Routes             []muxRoute
+		MethodNotAllowed   string
+		NotAllowedPatterns []string
+		ScopedNotAllowed   []muxNotAllowed
+		NotFound           string
+		Recover            bool
+		Mounts             []Mount
+		Interface          bool
+	}{
+		Routes:             routes,
+		MethodNotAllowed:   methodNotAllowed,
+		NotAllowedPatterns: notAllowedPatterns,
+		ScopedNotAllowed:   scopedNotAllowed,
+		NotFound:           notFound,
+		Recover:            cfg.PanicHandler != nil,
+		Mounts:             cfg.Mounts,
+		Interface:          g.Interface,
+	}
+	if err = g.writeTpl(t, data); err != nil {
+		return err
+	}
+	if err = g.generateHelperFuncs(cfg); err != nil {
+		return fmt.Errorf("generateHelperFuncs -> %w", err)
+	}
+	if err = g.generateParamsStructs(cfg); err != nil {
+		return fmt.Errorf("generateParamsStructs -> %w", err)
+	}
+	return nil
+}
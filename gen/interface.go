@@ -0,0 +1,145 @@
+package gen
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+// handlersMethod is the method of the Handlers interface for a handler function of the routes.
+type handlersMethod struct {
+	Name string
+	// Routes are the routes handled by the method such as "GET /api/users/:user_id".
+	Routes []string
+	// Params are the parameters of the method following w and r.
+	Params []param
+}
+
+// handlersMethodName returns the name of the method of the Handlers interface for the handler function,
+// which is qualified by the package if the handlers of the other packages have the same name.
+func handlersMethodName(h stdrouter.HandlerFunc, qualified bool) string {
+	name := stdrouter.SnakeToGoName(h.Func)
+	if qualified {
+		name = stdrouter.SnakeToGoName(h.Package) + name
+	}
+	return name
+}
+
+// collectHandlersMethods returns the methods of the Handlers interface in the order of the routes,
+// and the names of the methods by the handler functions as written in the router file.
+// The handler registered to the routes with different numbers of path params is reported as an error,
+// since the method cannot receive both.
+func (g *generator) collectHandlersMethods(cfg *config) ([]handlersMethod, map[string]string, error) {
+	routes := collectRoutes(cfg.Node)
+	funcs := map[string]map[string]bool{}
+	for _, rt := range routes {
+		name := handlersMethodName(rt.Handler, false)
+		if funcs[name] == nil {
+			funcs[name] = map[string]bool{}
+		}
+		funcs[name][handlerName(rt.Handler)] = true
+	}
+	var methods []handlersMethod
+	names := map[string]string{}
+	index := map[string]int{}
+	pathParams := map[string][]string{}
+	for _, rt := range routes {
+		handler := handlerName(rt.Handler)
+		params := stdrouter.PathParams(rt.Node)
+		r := strings.ToUpper(rt.Method) + " " + stdrouter.BuildPath(rt.Node)
+		if i, ok := index[handler]; ok {
+			if prev := pathParams[handler]; len(prev) != len(params) {
+				return nil, nil, fmt.Errorf("%s is registered to the routes with different numbers of path params: [%s] and [%s]",
+					handler, strings.Join(prev, ", "), strings.Join(params, ", "))
+			}
+			methods[i].Routes = append(methods[i].Routes, r)
+			continue
+		}
+		name := handlersMethodName(rt.Handler, len(funcs[handlersMethodName(rt.Handler, false)]) > 1)
+		for h, n := range names {
+			if n == name {
+				return nil, nil, fmt.Errorf("%s and %s have the same method name %s in Handlers", h, handler, name)
+			}
+		}
+		ps := g.handlerParams(rt)
+		index[handler] = len(methods)
+		pathParams[handler] = params
+		names[handler] = name
+		methods = append(methods, handlersMethod{Name: name, Routes: []string{r}, Params: ps})
+	}
+	return methods, names, nil
+}
+
+// generateHandlers generates the Handlers interface of the handlers of the routes passed to NewRouter.
+func (g *generator) generateHandlers(methods []handlersMethod) error {
+	t, err := g.parseTpl("Handlers")
+	if err != nil {
+		return err
+	}
+	return g.writeTpl(t, methods)
+}
+
+// routerImports returns the packages imported by the router file and used by the generated router.
+// With Options.Interface, the packages only qualifying the handlers of the routes are not used,
+// while the packages of the other handlers, the structs of the path params and the mounted handlers are.
+func (g *generator) routerImports(cfg *config) []string {
+	if !g.Interface {
+		return cfg.ImportedPkgs
+	}
+	routePkgs := map[string]bool{}
+	for _, rt := range cfg.Routes {
+		routePkgs[rt.Handler.Package] = true
+	}
+	used := map[string]bool{
+		cfg.NotFoundHandler.Package:         true,
+		cfg.MethodNotAllowedHandler.Package: true,
+	}
+	if cfg.PanicHandler != nil {
+		used[cfg.PanicHandler.Package] = true
+	}
+	for _, sh := range append(append([]scopedHandler{}, cfg.ScopedNotFound...), cfg.ScopedMethodNotAllowed...) {
+		used[sh.Handler.Package] = true
+	}
+	if g.Params == ParamsStruct {
+		for _, rt := range collectRoutes(cfg.Node) {
+			if len(stdrouter.PathParams(rt.Node)) != 0 {
+				used[rt.Handler.Package] = true
+			}
+		}
+	}
+	for _, m := range cfg.Mounts {
+		for _, name := range qualifiers(m.Handler) {
+			used[name] = true
+		}
+	}
+	var pkgs []string
+	for _, p := range cfg.ImportedPkgs {
+		name := importName(cfg.ImportNames, p)
+		if !routePkgs[name] || used[name] {
+			pkgs = append(pkgs, p)
+		}
+	}
+	return pkgs
+}
+
+// qualifiers returns the names qualifying the selector expressions in the Go expression,
+// such as "admin" of "admin.NewRouter()".
+func qualifiers(expr string) []string {
+	e, err := parser.ParseExpr(expr)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	ast.Inspect(e, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				names = append(names, id.Name)
+			}
+		}
+		return true
+	})
+	return names
+}
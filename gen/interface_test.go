@@ -0,0 +1,96 @@
+package gen
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGenerate_interface(t *testing.T) {
+	tests := []struct {
+		name    string
+		routes  []Route
+		opts    Options
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "methods qualified by the packages",
+			routes: []Route{
+				{Method: "GET", Pattern: "/users/:user_id", Handler: Handler{Package: "user", Func: "Get"}},
+				{Method: "GET", Pattern: "/posts/:post_id", Handler: Handler{Package: "post", Func: "Get"}},
+				{Method: "DELETE", Pattern: "/posts/:post_id", Handler: Handler{Package: "post", Func: "delete_post"}},
+			},
+			opts: Options{Interface: true},
+			want: []string{
+				"UserGet(w http.ResponseWriter, r *http.Request, userId string)",
+				"PostGet(w http.ResponseWriter, r *http.Request, postId string)",
+				"DeletePost(w http.ResponseWriter, r *http.Request, postId string)",
+				"h.PostGet(w, r, ",
+			},
+		},
+		{
+			name: "params in context",
+			routes: []Route{
+				{Method: "GET", Pattern: "/users/:user_id", Handler: Handler{Package: "user", Func: "Get"}},
+			},
+			opts: Options{Interface: true, Params: ParamsContext},
+			want: []string{
+				"Get(w http.ResponseWriter, r *http.Request)\n",
+				`h.Get(w, withParams(r, "user_id", userId))`,
+			},
+		},
+		{
+			name: "handler with different numbers of path params",
+			routes: []Route{
+				{Method: "GET", Pattern: "/users/:user_id", Handler: Handler{Package: "user", Func: "Get"}},
+				{Method: "GET", Pattern: "/users/:user_id/posts/:post_id", Handler: Handler{Package: "user", Func: "Get"}},
+			},
+			opts:    Options{Interface: true},
+			wantErr: true,
+		},
+		{
+			name: "methods of the same name",
+			routes: []Route{
+				{Method: "GET", Pattern: "/users", Handler: Handler{Package: "user", Func: "Get"}},
+				{Method: "GET", Pattern: "/posts", Handler: Handler{Func: "UserGet"}},
+				{Method: "GET", Pattern: "/", Handler: Handler{Package: "", Func: "Get"}},
+			},
+			opts:    Options{Interface: true},
+			wantErr: true,
+		},
+		{
+			name: "with tests",
+			routes: []Route{
+				{Method: "GET", Pattern: "/users", Handler: Handler{Package: "user", Func: "Get"}},
+			},
+			opts:    Options{Interface: true, Testable: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &RouterSpec{
+				PackageName: "main",
+				RouterName:  "r",
+				Imports:     []string{"net/http", "example.com/user", "example.com/post"},
+				Routes:      tt.routes,
+			}
+			got, err := Generate(spec, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			for _, want := range tt.want {
+				if !bytes.Contains(got, []byte(want)) {
+					t.Errorf("Generate() = \n%s, want to contain %s", got, want)
+				}
+			}
+			// the packages only qualifying the handlers of the routes are not imported
+			if bytes.Contains(got, []byte(`"example.com/user"`)) {
+				t.Errorf("Generate() = \n%s, want not to import example.com/user", got)
+			}
+		})
+	}
+}
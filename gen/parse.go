@@ -0,0 +1,537 @@
+package gen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/printer"
+	"go/scanner"
+	"go/token"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+// analyzer holds the state while analyzing a router file.
+type analyzer struct {
+	fset               *token.FileSet
+	spec               *RouterSpec
+	RouterInstanceName string
+	// groups are the prefixes of the groups by the variable names.
+	groups map[string]string
+	// diags are the problems found in the router file.
+	diags Diagnostics
+	// comments are the comments of the router file tied to the nodes.
+	comments ast.CommentMap
+}
+
+// Parse analyzes the router files and returns the route table declared in them.
+// The files must belong to the same package.
+// Every problem found in the files is reported at once as Diagnostics.
+func Parse(filenames ...string) (*RouterSpec, error) {
+	if len(filenames) == 0 {
+		return nil, fmt.Errorf("no router file")
+	}
+	spec := &RouterSpec{}
+	fset := token.NewFileSet()
+	var diags Diagnostics
+	for _, filename := range filenames {
+		ds, err := analyzeFile(fset, filename, spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filename, err)
+		}
+		diags = append(diags, ds...)
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+	return spec, nil
+}
+
+// analyzeFile adds the routes declared in the router file to the spec and returns the problems found in it.
+func analyzeFile(fset *token.FileSet, filename string, spec *RouterSpec) (Diagnostics, error) {
+	cfg := &analyzer{fset: fset, spec: spec, groups: map[string]string{}}
+	f, err := parser.ParseFile(cfg.fset, filename, nil, parser.AllErrors|parser.ParseComments)
+	var errs scanner.ErrorList
+	if errors.As(err, &errs) {
+		// one error per line as go build
+		errs.RemoveMultiples()
+		for _, e := range errs {
+			cfg.diags = append(cfg.diags, Diagnostic{Pos: e.Pos, Severity: SeverityError, Message: e.Msg})
+		}
+		return cfg.diags, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file -> %w", err)
+	}
+	cfg.comments = ast.NewCommentMap(fset, f, f.Comments)
+	if !spec.Pos.IsValid() {
+		// the warnings of the spec are reported at the beginning of the first file until the router is found
+		spec.Pos = fset.Position(f.Package)
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		var err error
+		switch v := n.(type) {
+		case *ast.File:
+			if err = setPackageName(v, cfg); err != nil {
+				err = fmt.Errorf("setPackageName -> %w", err)
+			}
+		case *ast.GenDecl:
+			if err = setImportedPkg(v, cfg); err != nil {
+				err = fmt.Errorf("setImportedPkg -> %w", err)
+			}
+		case *ast.FuncDecl:
+			if err = checkFuncDecl(v, cfg); err != nil {
+				err = fmt.Errorf("checkFuncDecl -> %w", err)
+			}
+		case *ast.AssignStmt:
+			if err = setRouterInstance(v, cfg); err != nil {
+				err = fmt.Errorf("setRouterInstance -> %w", err)
+			}
+		case *ast.ExprStmt:
+			routes := len(spec.Routes)
+			if err = registerHandler(v, cfg); err != nil {
+				err = fmt.Errorf("registerHandler -> %w", err)
+			} else if len(spec.Routes) > routes {
+				spec.Routes[routes].Doc = cfg.docOf(v)
+			}
+		default:
+			return true
+		}
+		if err != nil {
+			// report the problem and go on to the next node
+			cfg.report(n, err)
+			return false
+		}
+		return true
+	})
+	return cfg.diags, nil
+}
+
+// docOf returns the text of the comment tied to the statement:
+// the comment just before the statement, or the comment at the end of the line if there is none.
+func (cfg *analyzer) docOf(stmt ast.Stmt) string {
+	var doc, trailing string
+	for _, cg := range cfg.comments[stmt] {
+		switch {
+		case cg.End() < stmt.Pos():
+			doc = cg.Text()
+		case cfg.fset.Position(cg.Pos()).Line == cfg.fset.Position(stmt.End()).Line:
+			trailing = cg.Text()
+		}
+	}
+	if doc == "" {
+		doc = trailing
+	}
+	return strings.TrimSpace(doc)
+}
+
+// errorAt returns the error at the position of the node.
+func (cfg *analyzer) errorAt(node ast.Node, format string, args ...interface{}) error {
+	return &posError{pos: cfg.fset.Position(node.Pos()), msg: fmt.Sprintf(format, args...)}
+}
+
+// report adds the error found while analyzing the node to the diagnostics.
+// The error is reported at the position of the node unless it has its own position.
+func (cfg *analyzer) report(node ast.Node, err error) {
+	d := Diagnostic{Pos: cfg.fset.Position(node.Pos()), Severity: SeverityError, Message: err.Error()}
+	var pe *posError
+	if errors.As(err, &pe) {
+		d.Pos, d.Message = pe.pos, pe.msg
+	}
+	cfg.diags = append(cfg.diags, d)
+}
+
+func setPackageName(file *ast.File, cfg *analyzer) error {
+	if cfg.spec.PackageName != "" && cfg.spec.PackageName != file.Name.Name {
+		return cfg.errorAt(file.Name, "router files in different packages: %s and %s", cfg.spec.PackageName, file.Name.Name)
+	}
+	cfg.spec.PackageName = file.Name.Name
+	return nil
+}
+
+// setImportedPkg adds imported packages to RouterSpec. It does not handle CONST, TYPE and VAR.
+func setImportedPkg(genDecl *ast.GenDecl, cfg *analyzer) error {
+	if genDecl.Tok != token.IMPORT {
+		return nil
+	}
+	for _, spec := range genDecl.Specs {
+		importSpec, ok := spec.(*ast.ImportSpec)
+		if !ok {
+			return nil
+		}
+		p, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			return cfg.errorAt(importSpec.Path, "invalid import path: %s", importSpec.Path.Value)
+		}
+		cfg.spec.Imports = append(cfg.spec.Imports, p)
+		var name string
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+		} else {
+			name = packageName(p, filepath.Dir(cfg.fset.Position(importSpec.Pos()).Filename))
+		}
+		if name != path.Base(p) && name != "_" && name != "." {
+			if cfg.spec.ImportNames == nil {
+				cfg.spec.ImportNames = map[string]string{}
+			}
+			cfg.spec.ImportNames[p] = name
+		}
+	}
+	return nil
+}
+
+// majorVersion matches the major version suffix of the import path such as "v2" of "example.com/api/v2".
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// gopkgVersion matches the version of the import path of gopkg.in such as ".v2" of "gopkg.in/yaml.v2".
+var gopkgVersion = regexp.MustCompile(`\.v[0-9]+$`)
+
+// packageName returns the name in the package clause of the package imported from the directory.
+// The packages of the module are read from the directories, and the ones outside the module are found by the go command.
+// The standard packages and the packages not found are named after the last element of the path
+// without the major version suffix.
+func packageName(p, dir string) string {
+	var bp *build.Package
+	var err error
+	if root, modPath, modErr := stdrouter.FindModule(dir); modErr == nil && stdrouter.InModule(p, modPath) {
+		bp, err = build.ImportDir(stdrouter.ModuleDir(root, modPath, p), 0)
+	} else if strings.Contains(strings.Split(p, "/")[0], ".") {
+		bp, err = build.Import(p, dir, 0)
+	}
+	if err == nil && bp != nil && bp.Name != "" {
+		return bp.Name
+	}
+	elems := strings.Split(p, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && majorVersion.MatchString(name) {
+		name = elems[len(elems)-2]
+	}
+	return gopkgVersion.ReplaceAllString(name, "")
+}
+
+func checkFuncDecl(funcDecl *ast.FuncDecl, cfg *analyzer) error {
+	funcName := funcDecl.Name.Name
+	if funcName != "NewRouter" {
+		return cfg.errorAt(funcDecl.Name, "invalid function declaration. want: NewRouter, got: %v", funcName)
+	}
+	return nil
+}
+
+func setRouterInstance(assignStmt *ast.AssignStmt, cfg *analyzer) error {
+	routerIdent, ok := assignStmt.Lhs[0].(*ast.Ident)
+	if !ok {
+		return cfg.errorAt(assignStmt.Lhs[0], "syntax error: the router must be assigned to a variable")
+	}
+
+	callExpr, ok := assignStmt.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	packageIdent, ok := selectorExpr.X.(*ast.Ident)
+	if !ok {
+		return cfg.errorAt(selectorExpr.X, "syntax error: the method must be called on a variable or a package")
+	}
+	// api := r.Group("/api")
+	if prefix, ok := cfg.prefixOf(packageIdent.Name); ok && selectorExpr.Sel.Name == "Group" {
+		return registerGroup(routerIdent.Name, prefix, callExpr, cfg)
+	}
+	if packageIdent.Name != "stdrouter" {
+		return nil
+	}
+	if selectorExpr.Sel.Name != "NewRouter" {
+		return nil
+	}
+	cfg.RouterInstanceName = routerIdent.Name
+	if cfg.spec.RouterName == "" {
+		cfg.spec.RouterName = routerIdent.Name
+		cfg.spec.Pos = cfg.fset.Position(callExpr.Pos())
+	}
+	return nil
+}
+
+func registerHandler(exprStmt *ast.ExprStmt, cfg *analyzer) error {
+	callExpr, ok := exprStmt.X.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	// r.HandleFunc(...).Name("name")
+	if innerCallExpr, ok := selectorExpr.X.(*ast.CallExpr); ok && selectorExpr.Sel.Name == "Name" {
+		return registerRouteName(innerCallExpr, callExpr.Args, cfg)
+	}
+	routerIdent, ok := selectorExpr.X.(*ast.Ident)
+	if !ok {
+		return cfg.errorAt(selectorExpr.X, "syntax error: the method must be called on a variable")
+	}
+	// Check if the Ident is router instance or group
+	prefix, ok := cfg.prefixOf(routerIdent.Name)
+	if !ok {
+		return nil
+	}
+	methodName := selectorExpr.Sel.Name
+	switch methodName {
+	case "HandleFunc":
+		if err := registerHandleFunc(callExpr, cfg, prefix); err != nil {
+			return fmt.Errorf("registerHandleFunc -> %w", err)
+		}
+	case "HandleNotFound":
+		if err := registerHandleNotFound(callExpr, cfg, prefix); err != nil {
+			return fmt.Errorf("registerHandleNotFound -> %w", err)
+		}
+	case "HandleMethodNotAllowed":
+		if err := registerHandleMethodNotAllowed(callExpr, cfg, prefix); err != nil {
+			return fmt.Errorf("registerHandleMethodNotAllowed -> %w", err)
+		}
+	case "HandlePanic":
+		if prefix != "" {
+			return cfg.errorAt(callExpr, "HandlePanic is declared in the group %s", prefix)
+		}
+		if err := registerHandlePanic(callExpr, cfg); err != nil {
+			return fmt.Errorf("registerHandlePanic -> %w", err)
+		}
+	case "Mount":
+		if err := registerMount(callExpr, cfg, prefix); err != nil {
+			return fmt.Errorf("registerMount -> %w", err)
+		}
+	default:
+		return cfg.errorAt(selectorExpr.Sel, "unknown method called: %s", methodName)
+	}
+	return nil
+}
+
+// prefixOf returns the prefix of the path of the router instance or the group named name.
+// The prefix of the router instance is empty.
+func (cfg *analyzer) prefixOf(name string) (string, bool) {
+	if name == cfg.RouterInstanceName {
+		return "", true
+	}
+	prefix, ok := cfg.groups[name]
+	return prefix, ok
+}
+
+// joinPath returns the pattern of the path declared in the group of the prefix.
+func joinPath(prefix, p string) string {
+	if prefix == "" {
+		return p
+	}
+	if p == "/" || p == "" {
+		return prefix
+	}
+	return prefix + "/" + strings.TrimPrefix(p, "/")
+}
+
+// registerGroup registers the group named name declared in the router or the group of the prefix.
+func registerGroup(name, prefix string, callExpr *ast.CallExpr, cfg *analyzer) error {
+	args := callExpr.Args
+	if len(args) != 1 {
+		return cfg.errorAt(callExpr, "invalid number of arguments to Group. got %d, want 1", len(args))
+	}
+	p, err := parseString(args[0], "the prefix of Group", cfg)
+	if err != nil {
+		return err
+	}
+	p = path.Clean("/" + p)
+	if p == "/" {
+		return cfg.errorAt(args[0], "group of the root: %s", name)
+	}
+	cfg.groups[name] = joinPath(prefix, p)
+	return nil
+}
+
+// parseString returns the value of the string literal. The argument is reported as what in the error.
+func parseString(expr ast.Expr, what string, cfg *analyzer) (string, error) {
+	basicLit, ok := expr.(*ast.BasicLit)
+	if !ok || basicLit.Kind != token.STRING {
+		return "", cfg.errorAt(expr, "%s must be a string literal", what)
+	}
+	s, err := strconv.Unquote(basicLit.Value)
+	if err != nil {
+		return "", cfg.errorAt(expr, "invalid string literal: %s", basicLit.Value)
+	}
+	return s, nil
+}
+
+// parseHandler returns the handler function named by the identifier or the qualified identifier such as handler.GetUser.
+func parseHandler(expr ast.Expr, cfg *analyzer) (Handler, error) {
+	switch v := expr.(type) {
+	case *ast.Ident:
+		return Handler{Func: v.Name}, nil
+	case *ast.SelectorExpr:
+		if pkg, ok := v.X.(*ast.Ident); ok {
+			return Handler{Package: pkg.Name, Func: v.Sel.Name}, nil
+		}
+	}
+	return Handler{}, cfg.errorAt(expr, "the handler must be a function name such as handler.GetUser")
+}
+
+func registerHandleFunc(callExpr *ast.CallExpr, cfg *analyzer, prefix string) error {
+	args := callExpr.Args
+	if len(args) != 3 {
+		return cfg.errorAt(callExpr, "invalid number of arguments to HandleFunc. got %d, want 3", len(args))
+	}
+	// check path
+	if basicLit, ok := args[0].(*ast.BasicLit); ok && basicLit.Kind != token.STRING {
+		return cfg.errorAt(args[0], "the type of first argument is invalid. want: string, got: %s", strings.ToLower(basicLit.Kind.String()))
+	}
+	pattern, err := parseString(args[0], "the path of HandleFunc", cfg)
+	if err != nil {
+		return err
+	}
+
+	// check method
+	methodSelectorExpr, ok := args[1].(*ast.SelectorExpr)
+	if !ok {
+		return cfg.errorAt(args[1], "method must be chosen from the http package")
+	}
+	ident, ok := methodSelectorExpr.X.(*ast.Ident)
+	if !ok || ident.Name != "http" {
+		return cfg.errorAt(args[1], "method must be chosen from the http package")
+	}
+	httpMethod := methodSelectorExpr.Sel.Name
+	if !stdrouter.Contains(httpMethod, stdrouter.HTTPMethods) {
+		return cfg.errorAt(methodSelectorExpr.Sel, "method not found. got: %v", httpMethod)
+	}
+	httpMethod = strings.ToUpper(strings.TrimPrefix(httpMethod, "Method"))
+
+	// check handler func
+	h, err := parseHandler(args[2], cfg)
+	if err != nil {
+		return err
+	}
+	cfg.spec.Routes = append(cfg.spec.Routes, Route{
+		Method:  httpMethod,
+		Pattern: joinPath(prefix, pattern),
+		Handler: h,
+		Pos:     cfg.fset.Position(callExpr.Pos()),
+	})
+	return nil
+}
+
+// registerRouteName registers the route declared in the HandleFunc call with the name.
+func registerRouteName(callExpr *ast.CallExpr, args []ast.Expr, cfg *analyzer) error {
+	selectorExpr, ok := callExpr.Fun.(*ast.SelectorExpr)
+	if !ok || selectorExpr.Sel.Name != "HandleFunc" {
+		return cfg.errorAt(callExpr, "Name must be called on HandleFunc")
+	}
+	if len(args) != 1 {
+		return cfg.errorAt(callExpr, "invalid number of arguments to Name. got %d, want 1", len(args))
+	}
+	name, err := parseString(args[0], "the name of the route", cfg)
+	if err != nil {
+		return err
+	}
+	for _, rt := range cfg.spec.Routes {
+		if rt.Name == name {
+			return cfg.errorAt(args[0], "duplicate route name: %s", name)
+		}
+	}
+	n := len(cfg.spec.Routes)
+	if err := registerHandler(&ast.ExprStmt{X: callExpr}, cfg); err != nil {
+		return err
+	}
+	if len(cfg.spec.Routes) == n {
+		return cfg.errorAt(callExpr, "Name must be called on HandleFunc of the router")
+	}
+	cfg.spec.Routes[len(cfg.spec.Routes)-1].Name = name
+	return nil
+}
+
+func registerHandleNotFound(callExpr *ast.CallExpr, cfg *analyzer, prefix string) error {
+	args := callExpr.Args
+	if len(args) != 1 {
+		return cfg.errorAt(callExpr, "invalid number of arguments to HandleNotFound. got %d, want 1", len(args))
+	}
+	h, err := parseHandler(args[0], cfg)
+	if err != nil {
+		return err
+	}
+	if prefix != "" {
+		return addScopedHandler(&cfg.spec.ScopedNotFound, prefix, h, callExpr, cfg)
+	}
+	if cfg.spec.NotFound != nil {
+		return cfg.errorAt(callExpr, "duplicate declaration: HandleNotFound")
+	}
+	cfg.spec.NotFound = &h
+	return nil
+}
+
+func registerHandleMethodNotAllowed(callExpr *ast.CallExpr, cfg *analyzer, prefix string) error {
+	args := callExpr.Args
+	if len(args) != 1 {
+		return cfg.errorAt(callExpr, "invalid number of arguments to HandleMethodNotAllowed. got %d, want 1", len(args))
+	}
+	h, err := parseHandler(args[0], cfg)
+	if err != nil {
+		return err
+	}
+	if prefix != "" {
+		return addScopedHandler(&cfg.spec.ScopedMethodNotAllowed, prefix, h, callExpr, cfg)
+	}
+	if cfg.spec.MethodNotAllowed != nil {
+		return cfg.errorAt(callExpr, "duplicate declaration: MethodNotAllowed")
+	}
+	cfg.spec.MethodNotAllowed = &h
+	return nil
+}
+
+// addScopedHandler adds the handler declared in the group of the prefix.
+func addScopedHandler(handlers *[]ScopedHandler, prefix string, h Handler, callExpr *ast.CallExpr, cfg *analyzer) error {
+	for _, sh := range *handlers {
+		if sh.Prefix == prefix {
+			return cfg.errorAt(callExpr, "duplicate declaration in the group %s", prefix)
+		}
+	}
+	*handlers = append(*handlers, ScopedHandler{Prefix: prefix, Handler: h})
+	return nil
+}
+
+func registerHandlePanic(callExpr *ast.CallExpr, cfg *analyzer) error {
+	args := callExpr.Args
+	if len(args) != 1 {
+		return cfg.errorAt(callExpr, "invalid number of arguments to HandlePanic. got %d, want 1", len(args))
+	}
+	h, err := parseHandler(args[0], cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.spec.Panic != nil {
+		return cfg.errorAt(callExpr, "duplicate declaration: HandlePanic")
+	}
+	cfg.spec.Panic = &h
+	return nil
+}
+
+func registerMount(callExpr *ast.CallExpr, cfg *analyzer, prefix string) error {
+	args := callExpr.Args
+	if len(args) != 2 {
+		return cfg.errorAt(callExpr, "invalid number of arguments to Mount. got %d, want 2", len(args))
+	}
+	mountPrefix, err := parseString(args[0], "the prefix of Mount", cfg)
+	if err != nil {
+		return err
+	}
+	// the handler is any expression, which is written to the generated file as it is
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, cfg.fset, args[1]); err != nil {
+		return fmt.Errorf("printer.Fprint -> %w", err)
+	}
+	cfg.spec.Mounts = append(cfg.spec.Mounts, Mount{Prefix: joinPath(prefix, mountPrefix), Handler: buf.String()})
+	return nil
+}
@@ -0,0 +1,393 @@
+package gen
+
+import (
+	"errors"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func writeRouterFiles(t *testing.T, srcs []string) (dir string, filenames []string) {
+	dir, err := ioutil.TempDir("", "stdrouter")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir: %v", err)
+	}
+	for i, src := range srcs {
+		filename := filepath.Join(dir, "router"+strconv.Itoa(i)+".go")
+		if err := ioutil.WriteFile(filename, []byte(src), 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile: %v", err)
+		}
+		filenames = append(filenames, filename)
+	}
+	return dir, filenames
+}
+
+func TestParse(t *testing.T) {
+	const header = `package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+	"github.com/tetsuzawa/stdrouter/_example/handler"
+)
+`
+	tests := []struct {
+		name    string
+		srcs    []string
+		want    *RouterSpec
+		wantErr bool
+	}{
+		{
+			name: "routes and error handlers",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/api/users", http.MethodGet, handler.GetUsers)
+	r.HandleFunc("/api/users/:user_id", http.MethodDelete, handler.DeleteUser)
+	r.HandleNotFound(handler.NotFoundHandler)
+	r.HandleMethodNotAllowed(methodNotAllowed)
+	return r
+}
+`},
+			want: &RouterSpec{
+				PackageName: "main",
+				RouterName:  "r",
+				Imports:     []string{"net/http", "github.com/tetsuzawa/stdrouter", "github.com/tetsuzawa/stdrouter/_example/handler"},
+				Routes: []Route{
+					{Method: "GET", Pattern: "/api/users", Handler: Handler{Package: "handler", Func: "GetUsers"}},
+					{Method: "DELETE", Pattern: "/api/users/:user_id", Handler: Handler{Package: "handler", Func: "DeleteUser"}},
+				},
+				NotFound:         &Handler{Package: "handler", Func: "NotFoundHandler"},
+				MethodNotAllowed: &Handler{Func: "methodNotAllowed"},
+			},
+		},
+		{
+			name: "doc comments",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	// GetUsers returns the users.
+	// They are paginated.
+	r.HandleFunc("/api/users", http.MethodGet, handler.GetUsers)
+	r.HandleFunc("/api/users/:user_id", http.MethodGet, handler.GetUser).Name("get-user") // GetUser returns the user.
+	r.HandleFunc("/api/users/:user_id", http.MethodDelete, handler.DeleteUser)
+	// error handlers
+
+	r.HandleNotFound(handler.NotFoundHandler)
+	return r
+}
+`},
+			want: &RouterSpec{
+				PackageName: "main",
+				RouterName:  "r",
+				Imports:     []string{"net/http", "github.com/tetsuzawa/stdrouter", "github.com/tetsuzawa/stdrouter/_example/handler"},
+				Routes: []Route{
+					{Method: "GET", Pattern: "/api/users", Handler: Handler{Package: "handler", Func: "GetUsers"}, Doc: "GetUsers returns the users.\nThey are paginated."},
+					{Method: "GET", Pattern: "/api/users/:user_id", Handler: Handler{Package: "handler", Func: "GetUser"}, Name: "get-user", Doc: "GetUser returns the user."},
+					{Method: "DELETE", Pattern: "/api/users/:user_id", Handler: Handler{Package: "handler", Func: "DeleteUser"}},
+				},
+				NotFound: &Handler{Package: "handler", Func: "NotFoundHandler"},
+			},
+		},
+		{
+			name: "panic handler",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, handler.GetRoot)
+	r.HandlePanic(handler.PanicHandler)
+	return r
+}
+`},
+			want: &RouterSpec{
+				PackageName: "main",
+				RouterName:  "r",
+				Imports:     []string{"net/http", "github.com/tetsuzawa/stdrouter", "github.com/tetsuzawa/stdrouter/_example/handler"},
+				Routes: []Route{
+					{Method: "GET", Pattern: "/", Handler: Handler{Package: "handler", Func: "GetRoot"}},
+				},
+				Panic: &Handler{Package: "handler", Func: "PanicHandler"},
+			},
+		},
+		{
+			name: "mounts",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, handler.GetRoot)
+	r.Mount("/static", http.StripPrefix("/assets", http.FileServer(http.Dir("static"))))
+	return r
+}
+`},
+			want: &RouterSpec{
+				PackageName: "main",
+				RouterName:  "r",
+				Imports:     []string{"net/http", "github.com/tetsuzawa/stdrouter", "github.com/tetsuzawa/stdrouter/_example/handler"},
+				Routes: []Route{
+					{Method: "GET", Pattern: "/", Handler: Handler{Package: "handler", Func: "GetRoot"}},
+				},
+				Mounts: []Mount{
+					{Prefix: "/static", Handler: `http.StripPrefix("/assets", http.FileServer(http.Dir("static")))`},
+				},
+			},
+		},
+		{
+			name: "groups",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, handler.GetRoot)
+	api := r.Group("/api")
+	api.HandleFunc("/users", http.MethodGet, handler.GetUsers)
+	api.HandleNotFound(handler.APINotFoundHandler)
+	api.HandleMethodNotAllowed(handler.APIMethodNotAllowedHandler)
+	user := api.Group("users/:user_id/")
+	user.HandleFunc("/", http.MethodGet, handler.GetUser)
+	user.HandleNotFound(handler.UserNotFoundHandler)
+	r.HandleNotFound(handler.NotFoundHandler)
+	return r
+}
+`},
+			want: &RouterSpec{
+				PackageName: "main",
+				RouterName:  "r",
+				Imports:     []string{"net/http", "github.com/tetsuzawa/stdrouter", "github.com/tetsuzawa/stdrouter/_example/handler"},
+				Routes: []Route{
+					{Method: "GET", Pattern: "/", Handler: Handler{Package: "handler", Func: "GetRoot"}},
+					{Method: "GET", Pattern: "/api/users", Handler: Handler{Package: "handler", Func: "GetUsers"}},
+					{Method: "GET", Pattern: "/api/users/:user_id", Handler: Handler{Package: "handler", Func: "GetUser"}},
+				},
+				NotFound: &Handler{Package: "handler", Func: "NotFoundHandler"},
+				ScopedNotFound: []ScopedHandler{
+					{Prefix: "/api", Handler: Handler{Package: "handler", Func: "APINotFoundHandler"}},
+					{Prefix: "/api/users/:user_id", Handler: Handler{Package: "handler", Func: "UserNotFoundHandler"}},
+				},
+				ScopedMethodNotAllowed: []ScopedHandler{
+					{Prefix: "/api", Handler: Handler{Package: "handler", Func: "APIMethodNotAllowedHandler"}},
+				},
+			},
+		},
+		{
+			name: "duplicate NotFound handlers in the group",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	api := r.Group("/api")
+	api.HandleNotFound(handler.NotFoundHandler)
+	api.HandleNotFound(handler.NotFoundHandler)
+	return r
+}
+`},
+			wantErr: true,
+		},
+		{
+			name: "panic handler in the group",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	api := r.Group("/api")
+	api.HandlePanic(handler.PanicHandler)
+	return r
+}
+`},
+			wantErr: true,
+		},
+		{
+			name: "group of the root",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	root := r.Group("/")
+	root.HandleFunc("/", http.MethodGet, handler.GetRoot)
+	return r
+}
+`},
+			wantErr: true,
+		},
+		{
+			name: "routes split into files",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, handler.GetRoot)
+	return r
+}
+`, `package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+)
+
+func NewRouter() http.Handler {
+	router := stdrouter.NewRouter()
+	router.HandleFunc("/api", http.MethodPost, CreateAPI)
+	return router
+}
+`},
+			want: &RouterSpec{
+				PackageName: "main",
+				RouterName:  "r",
+				Imports:     []string{"net/http", "github.com/tetsuzawa/stdrouter", "github.com/tetsuzawa/stdrouter/_example/handler", "net/http", "github.com/tetsuzawa/stdrouter"},
+				Routes: []Route{
+					{Method: "GET", Pattern: "/", Handler: Handler{Package: "handler", Func: "GetRoot"}},
+					{Method: "POST", Pattern: "/api", Handler: Handler{Func: "CreateAPI"}},
+				},
+			},
+		},
+		{
+			name: "route names",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/api/users", http.MethodGet, handler.GetUsers)
+	r.HandleFunc("/api/users/:user_id", http.MethodGet, handler.GetUser).Name("get-user")
+	return r
+}
+`},
+			want: &RouterSpec{
+				PackageName: "main",
+				RouterName:  "r",
+				Imports:     []string{"net/http", "github.com/tetsuzawa/stdrouter", "github.com/tetsuzawa/stdrouter/_example/handler"},
+				Routes: []Route{
+					{Method: "GET", Pattern: "/api/users", Handler: Handler{Package: "handler", Func: "GetUsers"}},
+					{Method: "GET", Pattern: "/api/users/:user_id", Handler: Handler{Package: "handler", Func: "GetUser"}, Name: "get-user"},
+				},
+			},
+		},
+		{
+			name: "duplicate route names",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/api/users", http.MethodGet, handler.GetUsers).Name("users")
+	r.HandleFunc("/api/users", http.MethodPost, handler.CreateUser).Name("users")
+	return r
+}
+`},
+			wantErr: true,
+		},
+		{
+			name:    "files in different packages",
+			srcs:    []string{"package main\n", "package router\n"},
+			wantErr: true,
+		},
+		{
+			name: "unknown method",
+			srcs: []string{header + `
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGot, handler.GetRoot)
+	return r
+}
+`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, filenames := writeRouterFiles(t, tt.srcs)
+			defer os.RemoveAll(dir)
+
+			got, err := Parse(filenames...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != nil {
+				// the positions are tested in TestParse_diagnostics
+				got.Pos = token.Position{}
+				for i := range got.Routes {
+					got.Routes[i].Pos = token.Position{}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_diagnostics(t *testing.T) {
+	dir, filenames := writeRouterFiles(t, []string{`package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+	"github.com/tetsuzawa/stdrouter/_example/handler"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc(path, http.MethodGet, handler.GetRoot)
+	r.HandleFunc("/api", http.MethodGot, handler.GetAPIRoot)
+	r.HandleFunc("/api/users", http.MethodGet, handler.GetUsers)
+	r.HandleNotFound(func(w http.ResponseWriter, r *http.Request) {})
+	r.HandleNotFound(handler.NotFoundHandler)
+	r.HandleNotFound(handler.NotFoundHandler)
+	return r
+}
+`})
+	defer os.RemoveAll(dir)
+
+	_, err := Parse(filenames...)
+	var diags Diagnostics
+	if !errors.As(err, &diags) {
+		t.Fatalf("Parse() error = %v, want Diagnostics", err)
+	}
+	want := []string{
+		filenames[0] + ":12:15: the path of HandleFunc must be a string literal",
+		filenames[0] + ":13:28: method not found. got: MethodGot",
+		filenames[0] + ":15:19: the handler must be a function name such as handler.GetUser",
+		filenames[0] + ":17:2: duplicate declaration: HandleNotFound",
+	}
+	var got []string
+	for _, d := range diags {
+		if d.Severity != SeverityError {
+			t.Errorf("Severity of %v = %v, want %v", d, d.Severity, SeverityError)
+		}
+		got = append(got, d.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() diagnostics = %q, want %q", got, want)
+	}
+}
+
+func TestRouterSpec_Warnings_position(t *testing.T) {
+	dir, filenames := writeRouterFiles(t, []string{`package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+	"github.com/tetsuzawa/stdrouter/_example/handler"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, handler.GetRoot)
+	r.HandleNotFound(handler.NotFoundHandler)
+	return r
+}
+`})
+	defer os.RemoveAll(dir)
+
+	spec, err := Parse(filenames...)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []string{filenames[0] + ":11:7: warning: HandleMethodNotAllowed is not declared; the built-in handler replies with 405 Method Not Allowed"}
+	var got []string
+	for _, d := range spec.Warnings() {
+		got = append(got, d.String())
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RouterSpec.Warnings() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,104 @@
+package gen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+// stub is the function declared for a handler missing from its package.
+type stub struct {
+	Name string
+	// Handles is what the handler handles such as "GET /api/users/:user_id".
+	Handles string
+	// Params are the parameters of the function following w and r.
+	Params []param
+}
+
+// Stubs is the Go source of the stubs of the handlers missing from a package of the handlers.
+type Stubs struct {
+	// Package is the name qualifying the handlers in the router file, which is the name of the import
+	// or the name of the package, and empty for the package of the router file.
+	Package string
+	// Src is the Go source of the function declarations without the package clause and the imports.
+	// The functions use net/http.
+	Src []byte
+}
+
+// GenerateStubs generates the stubs of the handler functions referenced in the spec
+// and not declared in their packages, as reported by declared for the package and the name of the function.
+// The stubs take the path params as Options.Params passes them and reply with 501 Not Implemented.
+// The stubs are returned for each package in the order of the routes in the tree,
+// and the packages without missing handlers are omitted.
+func GenerateStubs(spec *RouterSpec, opts Options, declared func(pkg, name string) bool) ([]Stubs, error) {
+	if err := checkOptions(opts); err != nil {
+		return nil, err
+	}
+	if opts.Interface {
+		return nil, fmt.Errorf("stubs cannot be generated with interface, whose handlers are the methods")
+	}
+	cfg, err := newConfig(spec)
+	if err != nil {
+		return nil, fmt.Errorf("newConfig -> %w", err)
+	}
+	g := &generator{Options: opts}
+	var pkgs []string
+	stubs := map[string][]stub{}
+	index := map[string]int{}
+	add := func(h stdrouter.HandlerFunc, handles string, params []param) {
+		name := handlerName(h)
+		if i, ok := index[name]; ok {
+			stubs[h.Package][i].Handles += ", " + handles
+			return
+		}
+		if declared(h.Package, h.Func) {
+			return
+		}
+		if _, ok := stubs[h.Package]; !ok {
+			pkgs = append(pkgs, h.Package)
+		}
+		index[name] = len(stubs[h.Package])
+		stubs[h.Package] = append(stubs[h.Package], stub{Name: h.Func, Handles: handles, Params: params})
+	}
+	for _, rt := range collectRoutes(cfg.Node) {
+		// the stub is declared in the package of the handler, where the struct of the path params is not qualified
+		params := g.handlerParams(route{Method: rt.Method, Node: rt.Node, Handler: stdrouter.HandlerFunc{Func: rt.Handler.Func}})
+		add(rt.Handler, strings.ToUpper(rt.Method)+" "+stdrouter.BuildPath(rt.Node), params)
+	}
+	// the built-in handlers are generated in the router
+	addFallback := func(h stdrouter.HandlerFunc, handles string) {
+		if !isBuiltin(h) {
+			add(h, handles, nil)
+		}
+	}
+	addFallback(*cfg.NotFoundHandler, "the requests to the paths not found")
+	for _, sh := range cfg.ScopedNotFound {
+		addFallback(sh.Handler, "the requests to the paths not found under "+sh.Prefix)
+	}
+	addFallback(*cfg.MethodNotAllowedHandler, "the requests with the methods not allowed")
+	for _, sh := range cfg.ScopedMethodNotAllowed {
+		addFallback(sh.Handler, "the requests with the methods not allowed under "+sh.Prefix)
+	}
+	if cfg.PanicHandler != nil {
+		add(*cfg.PanicHandler, "the panics in the handlers", []param{{Name: "v", Type: "interface{}"}})
+	}
+
+	var res []Stubs
+	for _, pkg := range pkgs {
+		g := &generator{Options: opts}
+		t, err := g.parseTpl("Stubs")
+		if err != nil {
+			return nil, err
+		}
+		if err := g.writeTpl(t, stubs[pkg]); err != nil {
+			return nil, err
+		}
+		src, err := g.format()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, Stubs{Package: pkg, Src: src})
+	}
+	return res, nil
+}
@@ -0,0 +1,115 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGenerateStubs(t *testing.T) {
+	spec := &RouterSpec{
+		PackageName: "main",
+		RouterName:  "r",
+		Imports:     []string{"net/http", "example.com/handler"},
+		Routes: []Route{
+			{Method: "GET", Pattern: "/users", Handler: Handler{Package: "handler", Func: "GetUsers"}},
+			{Method: "GET", Pattern: "/users/:user_id", Handler: Handler{Package: "handler", Func: "GetUser"}},
+			{Method: "GET", Pattern: "/users/:user_id/profile", Handler: Handler{Package: "handler", Func: "GetUser"}},
+			{Method: "GET", Pattern: "/invoices/:invoice_id", Handler: Handler{Package: "handler", Func: "GetInvoice"}},
+			{Method: "GET", Pattern: "/", Handler: Handler{Func: "index"}},
+		},
+		NotFound: &Handler{Package: "handler", Func: "NotFound"},
+		Panic:    &Handler{Func: "recovered"},
+	}
+	declared := func(pkg, name string) bool {
+		return pkg == "handler" && (name == "GetUsers" || name == "NotFound")
+	}
+	tests := []struct {
+		name string
+		opts Options
+		want []Stubs
+	}{
+		{
+			name: "positional params",
+			opts: Options{},
+			want: []Stubs{
+				{Package: "", Src: []byte(`
+// index handles GET /.
+// It replies with 501 Not Implemented until it is implemented.
+func index(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "Not Implemented", http.StatusNotImplemented)
+}
+
+// recovered handles the panics in the handlers.
+// It replies with 501 Not Implemented until it is implemented.
+func recovered(w http.ResponseWriter, r *http.Request, v interface{}) {
+	http.Error(w, "Not Implemented", http.StatusNotImplemented)
+}
+`)},
+				{Package: "handler", Src: []byte(`
+// GetUser handles GET /users/:user_id, GET /users/:user_id/profile.
+// It replies with 501 Not Implemented until it is implemented.
+func GetUser(w http.ResponseWriter, r *http.Request, userId string) {
+	http.Error(w, "Not Implemented", http.StatusNotImplemented)
+}
+
+// GetInvoice handles GET /invoices/:invoice_id.
+// It replies with 501 Not Implemented until it is implemented.
+func GetInvoice(w http.ResponseWriter, r *http.Request, invoiceId string) {
+	http.Error(w, "Not Implemented", http.StatusNotImplemented)
+}
+`)},
+			},
+		},
+		{
+			name: "params structs",
+			opts: Options{Params: ParamsStruct},
+			want: []Stubs{
+				{Package: "", Src: []byte(`
+// index handles GET /.
+// It replies with 501 Not Implemented until it is implemented.
+func index(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "Not Implemented", http.StatusNotImplemented)
+}
+
+// recovered handles the panics in the handlers.
+// It replies with 501 Not Implemented until it is implemented.
+func recovered(w http.ResponseWriter, r *http.Request, v interface{}) {
+	http.Error(w, "Not Implemented", http.StatusNotImplemented)
+}
+`)},
+				{Package: "handler", Src: []byte(`
+// GetUser handles GET /users/:user_id, GET /users/:user_id/profile.
+// It replies with 501 Not Implemented until it is implemented.
+func GetUser(w http.ResponseWriter, r *http.Request, params GetUserParams) {
+	http.Error(w, "Not Implemented", http.StatusNotImplemented)
+}
+
+// GetInvoice handles GET /invoices/:invoice_id.
+// It replies with 501 Not Implemented until it is implemented.
+func GetInvoice(w http.ResponseWriter, r *http.Request, params GetInvoiceParams) {
+	http.Error(w, "Not Implemented", http.StatusNotImplemented)
+}
+`)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateStubs(spec, tt.opts, declared)
+			if err != nil {
+				t.Fatalf("GenerateStubs: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GenerateStubs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := GenerateStubs(spec, Options{Interface: true}, declared); err == nil {
+		t.Error("GenerateStubs() error = nil, want the error with Interface")
+	}
+	got, err := GenerateStubs(spec, Options{}, func(pkg, name string) bool { return true })
+	if err != nil || got != nil {
+		t.Errorf("GenerateStubs() = %q, %v, want nothing without missing handlers", got, err)
+	}
+}
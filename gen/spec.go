@@ -0,0 +1,374 @@
+package gen
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/internal/stdrouter"
+)
+
+// RouterSpec is the route table declared in the router file.
+type RouterSpec struct {
+	// PackageName is the name of the package of the router file.
+	PackageName string
+	// Pos is the position of the router declared in the router file, where the warnings are reported.
+	// It is invalid for the spec not parsed from the router file.
+	Pos token.Position
+	// RouterName is the name of the variable of the router in NewRouter.
+	RouterName string
+	// Imports are the paths of the packages imported in the router file.
+	Imports []string
+	// ImportNames are the names qualifying the imported packages in the router file keyed by the import paths,
+	// which are given in the import declarations or the package clauses, if they differ from the last elements of the paths.
+	ImportNames map[string]string
+	// Routes are the routes in the registration order.
+	Routes []Route
+	// NotFound is the handler called when no route matches the path.
+	// If it is nil, the built-in handler replying in Options.ErrorFormat is generated.
+	NotFound *Handler
+	// MethodNotAllowed is the handler called when the route does not accept the method.
+	// If it is nil, the built-in handler replying in Options.ErrorFormat with the Allow header is generated.
+	MethodNotAllowed *Handler
+	// ScopedNotFound are the NotFound handlers declared in the groups, which are called for the paths under the prefixes.
+	ScopedNotFound []ScopedHandler
+	// ScopedMethodNotAllowed are the MethodNotAllowed handlers declared in the groups,
+	// which are called for the routes under the prefixes.
+	ScopedMethodNotAllowed []ScopedHandler
+	// Mounts are the handlers mounted to the prefixes of the path in the registration order.
+	Mounts []Mount
+	// Panic is the handler called with the value recovered from the panic in the handlers.
+	// It is nil unless HandlePanic is declared.
+	Panic *Handler
+}
+
+// Route is the handler registered to the pair of the method and the path pattern.
+type Route struct {
+	// Method is the HTTP method such as "GET".
+	Method string
+	// Pattern is the path pattern. The path params are written with the ":" prefix like "/api/users/:user_id".
+	Pattern string
+	// Handler is the handler function of the route.
+	Handler Handler
+	// Name is the name of the route given with Name chained on HandleFunc. It is empty if not given.
+	Name string
+	// Doc is the text of the comment just before the HandleFunc call, or at the end of the line if there is none.
+	Doc string
+	// Pos is the position of the HandleFunc call in the router file.
+	// It is invalid for the route not parsed from the router file.
+	Pos token.Position
+}
+
+// Params returns the names of the path params in the pattern.
+func (r Route) Params() []string {
+	var params []string
+	for _, s := range strings.Split(r.Pattern, "/") {
+		if strings.HasPrefix(s, ":") {
+			params = append(params, s[1:])
+		}
+	}
+	return params
+}
+
+// ScopedHandler is the handler declared in the group of the prefix of the path.
+// The handler of the longest prefix enclosing the path is used.
+type ScopedHandler struct {
+	// Prefix is the prefix of the group such as "/api". The path params match any segment.
+	Prefix string
+	// Handler is the handler function.
+	Handler Handler
+}
+
+// Mount is the http.Handler which serves the requests under the prefix of the path with the prefix stripped.
+type Mount struct {
+	// Prefix is the prefix of the path such as "/admin".
+	Prefix string
+	// Handler is the Go expression of the http.Handler such as "admin.NewRouter()".
+	Handler string
+}
+
+// Handler is the handler function referenced in the router file.
+type Handler struct {
+	// Package is the name of the package qualifying the function. It is empty for the function in the same package.
+	Package string
+	// Func is the name of the function.
+	Func string
+}
+
+// String returns the qualified name of the handler function as written in the router file.
+func (h Handler) String() string {
+	if h.Package == "" {
+		return h.Func
+	}
+	return h.Package + "." + h.Func
+}
+
+// Methods are the HTTP methods available in the router file, in the canonical order.
+var Methods = func() []string {
+	var methods []string
+	for _, m := range stdrouter.HTTPMethods {
+		methods = append(methods, strings.ToUpper(strings.TrimPrefix(m, "Method")))
+	}
+	return methods
+}()
+
+// Built-in handlers generated unless the router file declares HandleNotFound and HandleMethodNotAllowed,
+// or if it declares them with the functions of the stdrouter package such as stdrouter.MethodNotAllowed.
+const (
+	defaultNotFound         = "defaultNotFound"
+	defaultMethodNotAllowed = "defaultMethodNotAllowed"
+)
+
+// builtinHandler returns the name of the built-in handler declared with the function of the stdrouter package.
+func (spec *RouterSpec) builtinHandler(h Handler) (string, bool) {
+	if p, ok := findImport(spec.Imports, spec.ImportNames, h.Package); !ok || p != stdrouterPkg {
+		return "", false
+	}
+	switch h.Func {
+	case "NotFound":
+		return defaultNotFound, true
+	case "MethodNotAllowed":
+		return defaultMethodNotAllowed, true
+	}
+	return "", false
+}
+
+// isBuiltin reports whether the handler function is the built-in handler generated in the router.
+func isBuiltin(h stdrouter.HandlerFunc) bool {
+	return h.Package == "" && (h.Func == defaultNotFound || h.Func == defaultMethodNotAllowed)
+}
+
+// Warnings returns the problems of the spec which do not prevent the generation.
+// They are reported at the position of the router.
+func (spec *RouterSpec) Warnings() Diagnostics {
+	var warnings Diagnostics
+	warn := func(msg string) {
+		warnings = append(warnings, Diagnostic{Pos: spec.Pos, Severity: SeverityWarning, Message: msg})
+	}
+	if len(spec.Routes) == 0 && len(spec.Mounts) == 0 {
+		warn("no routes are declared; every request is passed to the NotFound handler")
+	}
+	if spec.NotFound == nil {
+		warn("HandleNotFound is not declared; the built-in handler replies with 404 Not Found")
+	}
+	if spec.MethodNotAllowed == nil {
+		warn("HandleMethodNotAllowed is not declared; the built-in handler replies with 405 Method Not Allowed")
+	}
+	return warnings
+}
+
+// Conflicts returns the problems of the routes conflicting with the routes declared before them.
+// The route of the same method and pattern replaces the former one and is reported as a warning.
+// The path param named differently from the path param at the same position of another route is reported as an error,
+// since the router cannot tell which name to use.
+func (spec *RouterSpec) Conflicts() Diagnostics {
+	var diags Diagnostics
+	declared := make(map[string]Route)
+	params := make(map[string]Route)
+	for _, rt := range spec.Routes {
+		key := rt.Method + " " + rt.Pattern
+		if prev, ok := declared[key]; ok {
+			diags = append(diags, Diagnostic{
+				Pos:      rt.Pos,
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("duplicate route %s replaces the handler %s declared before", key, prev.Handler),
+			})
+		}
+		declared[key] = rt
+		segments := strings.Split(rt.Pattern, "/")
+		for i, s := range segments {
+			if !strings.HasPrefix(s, ":") {
+				continue
+			}
+			at := paramPosition(segments[:i])
+			prev, ok := params[at]
+			if !ok {
+				params[at] = rt
+				continue
+			}
+			if name := strings.Split(prev.Pattern, "/")[i]; name != s {
+				diags = append(diags, Diagnostic{
+					Pos:      rt.Pos,
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("path param %s of %s conflicts with %s of %s %s", s, rt.Pattern, name, prev.Method, prev.Pattern),
+				})
+				break
+			}
+		}
+	}
+	return diags
+}
+
+// paramPosition returns the key identifying the position of the path param after the segments,
+// where the path params are not distinguished by the names.
+func paramPosition(segments []string) string {
+	var key []string
+	for _, s := range segments {
+		if strings.HasPrefix(s, ":") {
+			s = ":"
+		}
+		key = append(key, s)
+	}
+	return strings.Join(key, "/")
+}
+
+// config is the router specification resolved for the generator.
+type config struct {
+	Node                    *stdrouter.Node
+	ImportedPkgs            []string
+	ImportNames             map[string]string
+	NotFoundHandler         *stdrouter.HandlerFunc
+	MethodNotAllowedHandler *stdrouter.HandlerFunc
+	PanicHandler            *stdrouter.HandlerFunc
+	// DefaultNotFound and DefaultMethodNotAllowed report whether the built-in handlers are generated,
+	// since the router file does not declare the handlers or declares the functions of the stdrouter package.
+	DefaultNotFound         bool
+	DefaultMethodNotAllowed bool
+	Routes                  []Route
+	Mounts                  []Mount
+	ScopedNotFound          []scopedHandler
+	ScopedMethodNotAllowed  []scopedHandler
+	PackageName             string
+	RouterInstanceName      string
+}
+
+// newConfig builds the tree of the routes in the spec.
+func newConfig(spec *RouterSpec) (*config, error) {
+	cfg := &config{
+		Node:               new(stdrouter.Node),
+		ImportedPkgs:       spec.Imports,
+		ImportNames:        spec.ImportNames,
+		Routes:             spec.Routes,
+		PackageName:        spec.PackageName,
+		RouterInstanceName: spec.RouterName,
+	}
+	notFound := cfg.handlerFunc(spec, spec.NotFound, defaultNotFound)
+	cfg.NotFoundHandler = &notFound
+	methodNotAllowed := cfg.handlerFunc(spec, spec.MethodNotAllowed, defaultMethodNotAllowed)
+	cfg.MethodNotAllowedHandler = &methodNotAllowed
+	if spec.Panic != nil {
+		cfg.PanicHandler = &stdrouter.HandlerFunc{Package: spec.Panic.Package, Func: spec.Panic.Func}
+	}
+	cfg.ScopedNotFound = cfg.newScopedHandlers(spec, spec.ScopedNotFound)
+	cfg.ScopedMethodNotAllowed = cfg.newScopedHandlers(spec, spec.ScopedMethodNotAllowed)
+	mounts, err := checkMounts(spec)
+	if err != nil {
+		return nil, fmt.Errorf("checkMounts -> %w", err)
+	}
+	cfg.Mounts = mounts
+	for _, rt := range spec.Routes {
+		if !stdrouter.Contains(rt.Method, Methods) {
+			return nil, fmt.Errorf("unknown method: %s %s", rt.Method, rt.Pattern)
+		}
+		h := stdrouter.HandlerFunc{Package: rt.Handler.Package, Func: rt.Handler.Func}
+		if err := cfg.Node.Add(rt.Pattern, rt.Method, h); err != nil {
+			return nil, fmt.Errorf("Node.Add -> %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// handlerFunc returns the function of the handler declared in the router file, or the built-in handler named def if h is nil.
+// The functions of the stdrouter package are replaced with the built-in handlers, which are marked to be generated.
+func (cfg *config) handlerFunc(spec *RouterSpec, h *Handler, def string) stdrouter.HandlerFunc {
+	name := def
+	if h != nil {
+		b, ok := spec.builtinHandler(*h)
+		if !ok {
+			return stdrouter.HandlerFunc{Package: h.Package, Func: h.Func}
+		}
+		name = b
+	}
+	switch name {
+	case defaultNotFound:
+		cfg.DefaultNotFound = true
+	case defaultMethodNotAllowed:
+		cfg.DefaultMethodNotAllowed = true
+	}
+	return stdrouter.HandlerFunc{Func: name}
+}
+
+// checkMounts returns the mounts with the cleaned prefixes in the order of the precedence, the longest prefix first.
+// The prefix with path params, the duplicate prefix, and the route and the handlers of the group under the prefix
+// are reported as errors.
+func checkMounts(spec *RouterSpec) ([]Mount, error) {
+	var mounts []Mount
+	for _, m := range spec.Mounts {
+		prefix := path.Clean("/" + m.Prefix)
+		if prefix == "/" {
+			return nil, fmt.Errorf("mount to the root: %s", m.Handler)
+		}
+		if strings.Contains(prefix, "/:") {
+			return nil, fmt.Errorf("mount prefix with path params: %s", m.Prefix)
+		}
+		for _, prev := range mounts {
+			if prev.Prefix == prefix {
+				return nil, fmt.Errorf("duplicate mount prefix: %s", m.Prefix)
+			}
+		}
+		for _, rt := range spec.Routes {
+			if rt.Pattern == prefix || strings.HasPrefix(rt.Pattern, prefix+"/") {
+				return nil, fmt.Errorf("route %s %s is under the mount prefix %s", rt.Method, rt.Pattern, m.Prefix)
+			}
+		}
+		// the mounted handler serves every path under the prefix, so the handlers of the group are never called
+		for _, sh := range append(append([]ScopedHandler{}, spec.ScopedNotFound...), spec.ScopedMethodNotAllowed...) {
+			if p := path.Clean("/" + sh.Prefix); p == prefix || strings.HasPrefix(p, prefix+"/") {
+				return nil, fmt.Errorf("handler %s of the group %s is under the mount prefix %s", sh.Handler, sh.Prefix, m.Prefix)
+			}
+		}
+		mounts = append(mounts, Mount{Prefix: prefix, Handler: m.Handler})
+	}
+	sort.SliceStable(mounts, func(i, j int) bool {
+		return len(mounts[i].Prefix) > len(mounts[j].Prefix)
+	})
+	return mounts, nil
+}
+
+// scopedHandler is the handler resolved for the generator with the cleaned prefix.
+type scopedHandler struct {
+	Prefix  string
+	Handler stdrouter.HandlerFunc
+}
+
+// newScopedHandlers returns the handlers in the order of the precedence, the longest prefix first.
+func (cfg *config) newScopedHandlers(spec *RouterSpec, handlers []ScopedHandler) []scopedHandler {
+	var res []scopedHandler
+	for _, sh := range handlers {
+		h := sh.Handler
+		res = append(res, scopedHandler{
+			Prefix:  path.Clean("/" + sh.Prefix),
+			Handler: cfg.handlerFunc(spec, &h, ""),
+		})
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return strings.Count(res[i].Prefix, "/") > strings.Count(res[j].Prefix, "/")
+	})
+	return res
+}
+
+// underPrefix reports whether the path p is under the prefix. The path params in the prefix match any segment.
+func underPrefix(p, prefix string) bool {
+	ps, prefixes := strings.Split(p, "/"), strings.Split(prefix, "/")
+	if len(ps) < len(prefixes) {
+		return false
+	}
+	for i, s := range prefixes {
+		if s != ps[i] && !(strings.HasPrefix(s, ":") && ps[i] != "") {
+			return false
+		}
+	}
+	return true
+}
+
+// methodNotAllowedFor returns the MethodNotAllowed handler of the group nearest to the route pattern.
+func (cfg *config) methodNotAllowedFor(pattern string) stdrouter.HandlerFunc {
+	for _, sh := range cfg.ScopedMethodNotAllowed {
+		if underPrefix(pattern, sh.Prefix) {
+			return sh.Handler
+		}
+	}
+	return *cfg.MethodNotAllowedHandler
+}
@@ -0,0 +1,220 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRoute_Params(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string
+	}{
+		{
+			name:    "without path params",
+			pattern: "/api/users",
+			want:    nil,
+		},
+		{
+			name:    "with path params",
+			pattern: "/api/users/:user_id/posts/:post_id",
+			want:    []string{"user_id", "post_id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (Route{Pattern: tt.pattern}).Params(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Route.Params() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckMounts(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    *RouterSpec
+		want    []Mount
+		wantErr bool
+	}{
+		{
+			name: "longest prefix first",
+			spec: &RouterSpec{Mounts: []Mount{
+				{Prefix: "/admin", Handler: "admin.NewRouter()"},
+				{Prefix: "/admin/static/", Handler: "static"},
+			}},
+			want: []Mount{
+				{Prefix: "/admin/static", Handler: "static"},
+				{Prefix: "/admin", Handler: "admin.NewRouter()"},
+			},
+		},
+		{
+			name:    "root",
+			spec:    &RouterSpec{Mounts: []Mount{{Prefix: "/", Handler: "h"}}},
+			wantErr: true,
+		},
+		{
+			name:    "path params",
+			spec:    &RouterSpec{Mounts: []Mount{{Prefix: "/users/:user_id", Handler: "h"}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate prefix",
+			spec: &RouterSpec{Mounts: []Mount{
+				{Prefix: "/admin", Handler: "h"},
+				{Prefix: "/admin/", Handler: "h"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "route under the prefix",
+			spec: &RouterSpec{
+				Routes: []Route{{Method: "GET", Pattern: "/admin/users", Handler: Handler{Func: "getUsers"}}},
+				Mounts: []Mount{{Prefix: "/admin", Handler: "h"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "NotFound handler of the group of the prefix",
+			spec: &RouterSpec{
+				ScopedNotFound: []ScopedHandler{{Prefix: "/admin/", Handler: Handler{Func: "adminNotFound"}}},
+				Mounts:         []Mount{{Prefix: "/admin", Handler: "h"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "MethodNotAllowed handler of the group under the prefix",
+			spec: &RouterSpec{
+				ScopedMethodNotAllowed: []ScopedHandler{{Prefix: "/admin/:id", Handler: Handler{Func: "methodNotAllowed"}}},
+				Mounts:                 []Mount{{Prefix: "/admin", Handler: "h"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "handler of the group enclosing the prefix",
+			spec: &RouterSpec{
+				ScopedNotFound: []ScopedHandler{{Prefix: "/admin", Handler: Handler{Func: "adminNotFound"}}},
+				Mounts:         []Mount{{Prefix: "/admin/static", Handler: "h"}},
+			},
+			want: []Mount{{Prefix: "/admin/static", Handler: "h"}},
+		},
+		{
+			name: "route sharing the beginning of the prefix",
+			spec: &RouterSpec{
+				Routes: []Route{{Method: "GET", Pattern: "/administrators", Handler: Handler{Func: "getAdministrators"}}},
+				Mounts: []Mount{{Prefix: "/admin", Handler: "h"}},
+			},
+			want: []Mount{{Prefix: "/admin", Handler: "h"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := checkMounts(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkMounts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("checkMounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnderPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		p      string
+		prefix string
+		want   bool
+	}{
+		{name: "prefix itself", p: "/api", prefix: "/api", want: true},
+		{name: "under the prefix", p: "/api/users/1", prefix: "/api", want: true},
+		{name: "sharing the beginning of the prefix", p: "/apis", prefix: "/api", want: false},
+		{name: "shorter than the prefix", p: "/api", prefix: "/api/users", want: false},
+		{name: "path param in the prefix", p: "/api/users/1/posts", prefix: "/api/users/:user_id", want: true},
+		{name: "empty segment for the path param", p: "/api/users/", prefix: "/api/users/:user_id", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := underPrefix(tt.p, tt.prefix); got != tt.want {
+				t.Errorf("underPrefix(%q, %q) = %v, want %v", tt.p, tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterSpec_Warnings(t *testing.T) {
+	h := &Handler{Func: "h"}
+	tests := []struct {
+		name string
+		spec *RouterSpec
+		want int
+	}{
+		{
+			name: "every handler declared",
+			spec: &RouterSpec{Routes: []Route{{Method: "GET", Pattern: "/", Handler: *h}}, NotFound: h, MethodNotAllowed: h},
+			want: 0,
+		},
+		{
+			name: "error handlers not declared",
+			spec: &RouterSpec{Routes: []Route{{Method: "GET", Pattern: "/", Handler: *h}}},
+			want: 2,
+		},
+		{
+			name: "no routes",
+			spec: &RouterSpec{NotFound: h, MethodNotAllowed: h},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.spec.Warnings(); len(got) != tt.want {
+				t.Errorf("RouterSpec.Warnings() = %q, want %d warnings", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRouterSpec_Conflicts(t *testing.T) {
+	route := func(method, pattern string) Route {
+		return Route{Method: method, Pattern: pattern, Handler: Handler{Func: "h"}}
+	}
+	tests := []struct {
+		name   string
+		routes []Route
+		want   []Severity
+	}{
+		{
+			name:   "no conflicts",
+			routes: []Route{route("GET", "/users/:id"), route("DELETE", "/users/:id"), route("GET", "/users/new"), route("GET", "/posts/:post_id")},
+		},
+		{
+			name:   "duplicate route",
+			routes: []Route{route("GET", "/users"), route("POST", "/users"), route("GET", "/users")},
+			want:   []Severity{SeverityWarning},
+		},
+		{
+			name:   "path params named differently",
+			routes: []Route{route("GET", "/users/:id"), route("GET", "/users/:user_id/posts"), route("GET", "/users/:id/posts/:post_id")},
+			want:   []Severity{SeverityError},
+		},
+		{
+			name:   "path params named differently after path params",
+			routes: []Route{route("GET", "/users/:id/posts/:post_id"), route("GET", "/users/:id/posts/:id")},
+			want:   []Severity{SeverityError},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := &RouterSpec{Routes: tt.routes}
+			var got []Severity
+			for _, d := range spec.Conflicts() {
+				got = append(got, d.Severity)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RouterSpec.Conflicts() = %q, want severities %q", spec.Conflicts(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package gen
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// GenerateStarter generates the starter router file of the package named pkg.
+// The router file has the stdrouter build tag, the go:generate directive, NewRouter without routes,
+// and http.NotFound and stdrouter.MethodNotAllowed as the NotFound and MethodNotAllowed handlers.
+func GenerateStarter(pkg string, opts Options) ([]byte, error) {
+	if err := checkOptions(opts); err != nil {
+		return nil, err
+	}
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name: %s", pkg)
+	}
+	g := &generator{Options: opts}
+	t, err := g.parseTpl("StarterRouter")
+	if err != nil {
+		return nil, err
+	}
+	if err := g.writeTpl(t, pkg); err != nil {
+		return nil, err
+	}
+	return g.format()
+}
@@ -0,0 +1,58 @@
+package gen
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateStarter(t *testing.T) {
+	starter, err := GenerateStarter("api", Options{})
+	if err != nil {
+		t.Fatalf("GenerateStarter: %v", err)
+	}
+	for _, want := range []string{"//go:build stdrouter\n// +build stdrouter\n", "//go:generate stdrouter\n", "package api\n"} {
+		if !bytes.Contains(starter, []byte(want)) {
+			t.Errorf("GenerateStarter() = \n%s, want to contain %s", starter, want)
+		}
+	}
+
+	// the starter router file is parsed and generated without problems
+	dir, err := ioutil.TempDir("", "stdrouter")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	name := filepath.Join(dir, "router.go")
+	if err := ioutil.WriteFile(name, starter, 0644); err != nil {
+		t.Fatalf("ioutil.WriteFile: %v", err)
+	}
+	spec, err := Parse(name)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if spec.PackageName != "api" || spec.NotFound == nil || spec.NotFound.String() != "http.NotFound" ||
+		spec.MethodNotAllowed == nil || spec.MethodNotAllowed.String() != "stdrouter.MethodNotAllowed" {
+		t.Errorf("Parse() = %+v, want the package api with http.NotFound and stdrouter.MethodNotAllowed", spec)
+	}
+	// only the warning of no routes is expected until the routes are declared
+	diags := append(spec.Conflicts(), spec.Warnings()...)
+	if len(diags) != 1 || !strings.HasPrefix(diags[0].Message, "no routes are declared") {
+		t.Errorf("diagnostics = %v, want only the warning of no routes", diags)
+	}
+	src, err := Generate(spec, Options{})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	// the built-in handler replies with 405 and the Allow header
+	if want := "func defaultMethodNotAllowed(w http.ResponseWriter, r *http.Request) {"; !bytes.Contains(src, []byte(want)) {
+		t.Errorf("Generate() = \n%s, want to contain %s", src, want)
+	}
+
+	if _, err := GenerateStarter("my-api", Options{}); err == nil {
+		t.Error("GenerateStarter() error = nil, want the error of the invalid package name")
+	}
+}
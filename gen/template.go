@@ -0,0 +1,869 @@
+package gen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	tplHeadMsg = `
+// Code generated by Standard Library Router Generator; DO NOT EDIT.
+
+{{ if .Generate }}//go:generate {{ .Generate }}
+{{ end }}//go:build !stdrouter{{ if .Constraint }} && {{ .Constraint }}{{ end }}
+// +build !stdrouter{{ if .Constraint }},{{ .Constraint }}{{ end }}
+
+`
+	tplPackage = `package {{ . }}
+`
+	tplImport = `import (
+`
+	tplClosingBracket = `)
+
+`
+	tplRouter = `type Router struct {
+{{- if .Interface }}
+	handlers Handlers
+{{- end }}
+{{- if .Mounts }}
+	mounts []mount
+{{ end -}}
+}
+
+func NewRouter({{ if .Interface }}h Handlers{{ end }}) http.Handler {
+	{{ .Name }} := &Router{ {{- if .Interface }}handlers: h{{ end -}} }
+{{- if .Mounts }}
+	{{ .Name }}.mounts = []mount{
+{{- range .Mounts }}
+		{prefix: {{ printf "%q" .Prefix }}, handler: {{ .Handler }}},
+{{- end }}
+	}
+{{- end }}
+	return {{ .Name }}
+}
+
+func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+{{- if .Recover }}
+	defer recoverPanic(w, r)
+{{- end }}
+{{- if .Mounts }}
+	if serveMount(router.mounts, w, r) {
+		return
+	}
+{{- end }}
+	handleBase({{ if .Interface }}router.handlers, {{ end }}w, r, r.URL.Path)
+}
+
+`
+	tplHandlers = `// Handlers are the handlers of the routes passed to NewRouter.
+type Handlers interface {
+{{- range . }}
+	// {{ .Name }} handles {{ range $i, $r := .Routes }}{{ if $i }}, {{ end }}{{ $r }}{{ end }}.
+	{{ .Name }}(w http.ResponseWriter, r *http.Request{{ range .Params }}, {{ .Name }} {{ .Type }}{{ end }})
+{{- end }}
+}
+
+`
+	tplServeMux = `func NewRouter({{ if .Interface }}h Handlers{{ end }}) http.Handler {
+	mux := http.NewServeMux()
+{{- range .Routes }}
+	mux.HandleFunc({{ printf "%q" .Pattern }}, func(w http.ResponseWriter, r *http.Request) {
+		{{ .Call }}
+	})
+{{- end }}
+{{- if .NotAllowedPatterns }}
+	methodNotAllowed := func(w http.ResponseWriter, r *http.Request) {
+		{{ .MethodNotAllowed }}
+	}
+	for _, pattern := range []string{
+{{- range .NotAllowedPatterns }}
+		{{ printf "%q" . }},
+{{- end }}
+	} {
+		mux.HandleFunc(pattern, methodNotAllowed)
+	}
+{{- end }}
+{{- range .ScopedNotAllowed }}
+	for _, pattern := range []string{
+{{- range .Patterns }}
+		{{ printf "%q" . }},
+{{- end }}
+	} {
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			{{ .Call }}
+		})
+	}
+{{- end }}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		{{ .NotFound }}
+	})
+{{- if .Mounts }}
+	mounts := []mount{
+{{- range .Mounts }}
+		{prefix: {{ printf "%q" .Prefix }}, handler: {{ .Handler }}},
+{{- end }}
+	}
+{{- end }}
+{{- if or .Recover .Mounts }}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+{{- if .Recover }}
+		defer recoverPanic(w, r)
+{{- end }}
+{{- if .Mounts }}
+		if serveMount(mounts, w, r) {
+			return
+		}
+{{- end }}
+		mux.ServeHTTP(w, r)
+	})
+{{- else }}
+	return mux
+{{- end }}
+}
+`
+	tplHandlerFunc = `func {{ .FuncName }}({{ if .Interface }}h Handlers, {{ end }}w http.ResponseWriter, r *http.Request, p string{{ range .PathParams }}, {{ . }} string{{ end }}) {
+`
+	tplSeparatePath = `endpoint, {{ .Tail }} := SeparatePath({{ .Base }}, {{ .Num }})
+`
+	tplSwitch = `switch {{ . }} {
+`
+	tplCase = `case {{ . }}:
+`
+	tplDefault = `default:
+`
+	tplImpl = `{{ . }}
+`
+	tplHandlerCall = `{{ .Func }}({{ .Args }})
+`
+	tplClosingCurlyBraces = `}
+
+`
+	tplIf = `if {{ . }} {
+`
+	tplElse = `} else {
+`
+
+	tplParamsFunc = `
+// paramsKey is the key of the path params in the context of the request.
+type paramsKey struct{}
+
+// withParams returns the request with the path params given as name and value pairs.
+func withParams(r *http.Request, nameValues ...string) *http.Request {
+	params := make(map[string]string, len(nameValues)/2)
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		params[nameValues[i]] = nameValues[i+1]
+	}
+	return r.WithContext(context.WithValue(r.Context(), paramsKey{}, params))
+}
+
+// Param returns the value of the path param matched by the router.
+// It returns the empty string if the route does not have the path param.
+func Param(r *http.Request, name string) string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	return params[name]
+}
+
+// Params returns the path params matched by the router.
+func Params(r *http.Request) map[string]string {
+	params, _ := r.Context().Value(paramsKey{}).(map[string]string)
+	res := make(map[string]string, len(params))
+	for k, v := range params {
+		res[k] = v
+	}
+	return res
+}
+`
+	tplRecoverFunc = `
+// recoverPanic passes the value recovered from the panic in the handlers to the panic handler.
+// It must be deferred directly.
+func recoverPanic(w http.ResponseWriter, r *http.Request) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	if v == http.ErrAbortHandler {
+		// the handler aborts the response on purpose
+		panic(v)
+	}
+	log.Printf("panic serving %s %s: %v\n%s", r.Method, r.URL.Path, v, debug.Stack())
+	{{ . }}
+}
+`
+	tplRouteTable = `
+// RouteInfo is the route registered to the router.
+type RouteInfo struct {
+	// Name is the name given with Name in the router file. It is empty if not given.
+	Name string
+	// Method is the HTTP method such as "GET".
+	Method string
+	// Pattern is the path pattern such as "/api/users/:user_id".
+	Pattern string
+	// Handler is the handler function as written in the router file.
+	Handler string
+	// Params are the names of the path params in the pattern.
+	Params []string
+}
+
+// Routes are the routes registered to the router in the order of the router file.
+var Routes = []RouteInfo{
+{{- range . }}
+	{Name: {{ printf "%q" .Name }}, Method: {{ printf "%q" .Method }}, Pattern: {{ printf "%q" .Pattern }}, Handler: {{ printf "%q" .Handler }}
+	{{- if .Params }}, Params: []string{ {{- range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ printf "%q" $p }}{{ end }}}{{ end }}},
+{{- end }}
+}
+
+// LookupRoute returns the route matching the method and the path, and the path params in the path.
+// The static segment takes precedence over the path param as in the router.
+func LookupRoute(method, p string) (RouteInfo, map[string]string, bool) {
+	segments := strings.Split(path.Clean("/"+p), "/")[1:]
+	var found RouteInfo
+	var foundParams map[string]string
+	for _, rt := range Routes {
+		if rt.Method != method {
+			continue
+		}
+		params, ok := matchRoute(rt.Pattern, segments)
+		if !ok {
+			continue
+		}
+		if foundParams != nil && !moreSpecific(rt.Pattern, found.Pattern) {
+			continue
+		}
+		found, foundParams = rt, params
+	}
+	return found, foundParams, foundParams != nil
+}
+
+// matchRoute returns the path params if the segments of the path match the pattern.
+func matchRoute(pattern string, segments []string) (map[string]string, bool) {
+	ps := strings.Split(pattern, "/")[1:]
+	if len(ps) != len(segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, s := range ps {
+		if strings.HasPrefix(s, ":") && segments[i] != "" {
+			params[s[1:]] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// moreSpecific reports whether the pattern a has the static segment first where a and b differ.
+func moreSpecific(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := range as {
+		if i >= len(bs) {
+			return false
+		}
+		aParam, bParam := strings.HasPrefix(as[i], ":"), strings.HasPrefix(bs[i], ":")
+		if aParam != bParam {
+			return bParam
+		}
+	}
+	return false
+}
+`
+	tplRouteFunc = `
+// routeKey is the key of the matched route in the context of the request.
+type routeKey struct{}
+
+// MatchedRoute is the route matched by the router.
+type MatchedRoute struct {
+	// Name is the name given with Name in the router file. It is empty if not given.
+	Name string
+	// Pattern is the path pattern such as "/api/users/:user_id".
+	Pattern string
+}
+
+// WithMatchedRoute returns the request holding the MatchedRoute which the router fills with the matched route.
+// The middleware outside of the router can read it after the router serves the request.
+// It is left empty if no route matches the request.
+func WithMatchedRoute(r *http.Request) (*http.Request, *MatchedRoute) {
+	m := &MatchedRoute{}
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, m)), m
+}
+
+// withRoute returns the request with the matched route.
+func withRoute(r *http.Request, name, pattern string) *http.Request {
+	if m, ok := r.Context().Value(routeKey{}).(*MatchedRoute); ok {
+		m.Name, m.Pattern = name, pattern
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), routeKey{}, &MatchedRoute{Name: name, Pattern: pattern}))
+}
+
+// RoutePattern returns the pattern of the route matched by the router, such as "/api/users/:user_id".
+// It returns the empty string if no route matches the request.
+func RoutePattern(r *http.Request) string {
+	if m, ok := r.Context().Value(routeKey{}).(*MatchedRoute); ok {
+		return m.Pattern
+	}
+	return ""
+}
+
+// RouteName returns the name of the route matched by the router.
+func RouteName(r *http.Request) string {
+	if m, ok := r.Context().Value(routeKey{}).(*MatchedRoute); ok {
+		return m.Name
+	}
+	return ""
+}
+`
+	tplMetricsFunc = `
+// routeMetrics are the metrics of the requests to a route.
+type routeMetrics struct {
+	requests     *expvar.Int
+	clientErrors *expvar.Int
+	serverErrors *expvar.Int
+	// latency is the cumulative histogram of the latency keyed by the upper bound in seconds.
+	latency    *expvar.Map
+	latencySum *expvar.Float
+}
+
+// latencyBuckets are the upper bounds of the buckets of the latency histograms in seconds.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metrics are the metrics of the routes published as {{ printf "%q" .Name }} in the expvar "stdrouter".
+var metrics = func() map[string]*routeMetrics {
+	published, ok := expvar.Get("stdrouter").(*expvar.Map)
+	if !ok {
+		published = expvar.NewMap("stdrouter")
+	}
+	if published.Get({{ printf "%q" .Name }}) != nil {
+		panic({{ printf "%q" (printf "stdrouter: metrics %q of another router are already published; regenerate the router with -metricsname" .Name) }})
+	}
+	router := new(expvar.Map).Init()
+	published.Set({{ printf "%q" .Name }}, router)
+	metrics := make(map[string]*routeMetrics)
+	for _, key := range []string{
+{{- range .Keys }}
+		{{ printf "%q" . }},
+{{- end }}
+	} {
+		m := &routeMetrics{
+			requests:     new(expvar.Int),
+			clientErrors: new(expvar.Int),
+			serverErrors: new(expvar.Int),
+			latency:      new(expvar.Map).Init(),
+			latencySum:   new(expvar.Float),
+		}
+		for _, b := range latencyBuckets {
+			m.latency.Add(strconv.FormatFloat(b, 'g', -1, 64), 0)
+		}
+		m.latency.Add("+Inf", 0)
+		v := new(expvar.Map).Init()
+		v.Set("requests", m.requests)
+		v.Set("4xx", m.clientErrors)
+		v.Set("5xx", m.serverErrors)
+		v.Set("latency_seconds", m.latency)
+		v.Set("latency_seconds_sum", m.latencySum)
+		router.Set(key, v)
+		metrics[key] = m
+	}
+	return metrics
+}()
+
+// observe records the request responded with the status in the duration.
+func (m *routeMetrics) observe(status int, d time.Duration) {
+	m.requests.Add(1)
+	switch {
+	case status >= 500:
+		m.serverErrors.Add(1)
+	case status >= 400:
+		m.clientErrors.Add(1)
+	}
+	s := d.Seconds()
+	m.latencySum.Add(s)
+	for _, b := range latencyBuckets {
+		if s <= b {
+			m.latency.Add(strconv.FormatFloat(b, 'g', -1, 64), 1)
+		}
+	}
+	m.latency.Add("+Inf", 1)
+}
+
+// track returns w wrapped to record the status of the response, and the function to be deferred
+// which records the request to the metrics of the key. The panic in the handler is counted as 500.
+func track(key string, w http.ResponseWriter) (http.ResponseWriter, func()) {
+	rec := &statusRecorder{ResponseWriter: w}
+	start := time.Now()
+	return rec, func() {
+		if v := recover(); v != nil {
+			metrics[key].observe(http.StatusInternalServerError, time.Since(start))
+			panic(v)
+		}
+		metrics[key].observe(rec.status, time.Since(start))
+	}
+}
+
+// statusRecorder is the http.ResponseWriter which records the status code of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if the original ResponseWriter does.
+func (w *statusRecorder) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker if the original ResponseWriter does.
+// The hijacked connection is counted as 101 Switching Protocols unless the status is written.
+func (w *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("stdrouter: the ResponseWriter does not implement http.Hijacker")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the original ResponseWriter for http.ResponseController.
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+`
+	tplMountFunc = `
+// mount is the http.Handler mounted to the prefix of the path.
+type mount struct {
+	prefix  string
+	handler http.Handler
+}
+
+// serveMount passes the request to the handler mounted to the prefix of the path with the prefix stripped.
+// It reports false if no handler is mounted to the path.
+func serveMount(mounts []mount, w http.ResponseWriter, r *http.Request) bool {
+	for _, m := range mounts {
+		if r.URL.Path != m.prefix && !strings.HasPrefix(r.URL.Path, m.prefix+"/") {
+			continue
+		}
+		r2 := new(http.Request)
+		*r2 = *r
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = "/" + strings.TrimPrefix(r.URL.Path[len(m.prefix):], "/")
+		r2.URL.RawPath = ""
+		if strings.HasPrefix(r.URL.RawPath, m.prefix) {
+			r2.URL.RawPath = "/" + strings.TrimPrefix(r.URL.RawPath[len(m.prefix):], "/")
+		}
+{{- if .Metrics }}
+		w, done := track("Mount "+m.prefix, w)
+		defer done()
+{{- end }}
+		m.handler.ServeHTTP(w, r2)
+		return true
+	}
+	return false
+}
+`
+	tplDefaultHandlers = `{{ range . }}
+// {{ .Name }} is the built-in handler replying to the request with {{ .Status }},
+// which is replaced with the handler declared by {{ .Declaration }} in the router file.
+func {{ .Name }}(w http.ResponseWriter, r *http.Request) {
+{{- if .ContentType }}
+	w.Header().Set("Content-Type", {{ printf "%q" .ContentType }})
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader({{ .Code }})
+	w.Write([]byte({{ printf "%q" .Body }}))
+{{- else }}
+	http.Error(w, {{ printf "%q" .Body }}, {{ .Code }})
+{{- end }}
+}
+{{ end }}`
+	tplNotFoundFunc = `
+// handleNotFound calls the NotFound handler of the group nearest to the path of the request.
+func handleNotFound(w http.ResponseWriter, r *http.Request) {
+	p := path.Clean("/" + r.URL.Path)
+	switch {
+{{- range .Scopes }}
+	case underPrefix(p, {{ printf "%q" .Prefix }}):
+		{{ .Call }}
+{{- end }}
+	default:
+		{{ .Default }}
+	}
+}
+
+// underPrefix reports whether the path p is under the prefix. The path params in the prefix match any segment.
+func underPrefix(p, prefix string) bool {
+	ps, prefixes := strings.Split(p, "/"), strings.Split(prefix, "/")
+	if len(ps) < len(prefixes) {
+		return false
+	}
+	for i, s := range prefixes {
+		if s != ps[i] && !(strings.HasPrefix(s, ":") && ps[i] != "") {
+			return false
+		}
+	}
+	return true
+}
+`
+	tplParamsStruct = `{{ range . }}
+// {{ .Name }} holds the path params passed to {{ .Handler }}.
+type {{ .Name }} struct {
+{{- range .Fields }}
+	{{ .Name }} string // {{ .Param }}
+{{- end }}
+}
+{{ end }}`
+	tplStarterRouter = `//go:build stdrouter
+// +build stdrouter
+
+// The build tag excludes the router file from the build, and stdrouter generates router_gen.go from it.
+// Run "go generate -tags stdrouter ." or "stdrouter" after changing the routes.
+
+//go:generate stdrouter
+
+package {{ . }}
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+)
+
+// NewRouter returns the router passing the requests to the handlers.
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	// Declare the routes with the path, the method and the handler, such as
+	// r.HandleFunc("/api/users/:user_id", http.MethodGet, handler.GetUser),
+	// where handler.GetUser is func(w http.ResponseWriter, r *http.Request, userId string).
+	r.HandleNotFound(http.NotFound)
+	// stdrouter.MethodNotAllowed is the built-in handler replying with 405 and the Allow header.
+	r.HandleMethodNotAllowed(stdrouter.MethodNotAllowed)
+	return r
+}
+`
+	tplParamsFile = `// Code generated by Standard Library Router Generator; DO NOT EDIT.
+
+package {{ . }}
+`
+	tplStubs = `{{ range . }}
+// {{ .Name }} handles {{ .Handles }}.
+// It replies with 501 Not Implemented until it is implemented.
+func {{ .Name }}(w http.ResponseWriter, r *http.Request{{ range .Params }}, {{ .Name }} {{ .Type }}{{ end }}) {
+	http.Error(w, "Not Implemented", http.StatusNotImplemented)
+}
+{{ end }}`
+	tplClient = `// Code generated by Standard Library Router Generator; DO NOT EDIT.
+
+// Package {{ .Package }} is the client of the routes of the router.
+package {{ .Package }}
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client sends the requests to the routes of the router.
+type Client struct {
+	// BaseURL is the URL of the server which the paths of the routes are appended to, such as "http://localhost:8080".
+	BaseURL string
+	// HTTPClient sends the requests. http.DefaultClient is used if it is nil.
+	HTTPClient *http.Client
+}
+
+// NewClient returns the client sending the requests to the server at baseURL.
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: baseURL}
+}
+
+// RequestOption modifies the request before it is sent.
+type RequestOption func(req *http.Request) error
+
+// WithHeader sets the header of the request.
+func WithHeader(key, value string) RequestOption {
+	return func(req *http.Request) error {
+		req.Header.Set(key, value)
+		return nil
+	}
+}
+
+// WithQuery adds the query parameters to the URL of the request.
+func WithQuery(query url.Values) RequestOption {
+	return func(req *http.Request) error {
+		q := req.URL.Query()
+		for key, values := range query {
+			for _, v := range values {
+				q.Add(key, v)
+			}
+		}
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
+
+// WithBody sets the body of the request and its Content-Type.
+func WithBody(contentType string, body io.Reader) RequestOption {
+	return func(req *http.Request) error {
+		r, err := http.NewRequest(req.Method, req.URL.String(), body)
+		if err != nil {
+			return err
+		}
+		req.Body, req.GetBody, req.ContentLength = r.Body, r.GetBody, r.ContentLength
+		req.Header.Set("Content-Type", contentType)
+		return nil
+	}
+}
+
+// do sends the request of the method to the path with the options.
+func (c *Client) do(ctx context.Context, method, p string, opts []RequestOption) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.BaseURL, "/")+p, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, opt := range opts {
+		if err := opt(req); err != nil {
+			return nil, err
+		}
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	return hc.Do(req)
+}
+{{ range .Methods }}
+// {{ .Name }} sends {{ .Method }} {{ .Pattern }}.
+{{- range .Doc }}
+//{{ if . }} {{ . }}{{ end }}
+{{- end }}
+func (c *Client) {{ .Name }}(ctx context.Context, {{ if .Params }}{{ .Params }}, {{ end }}opts ...RequestOption) (*http.Response, error) {
+	return c.do(ctx, {{ printf "%q" .Method }}, {{ .Path }}, opts)
+}
+{{ end -}}
+`
+	tplSeparatePathFunc = `
+func SeparatePath(p string, n int) (head, tail string) {
+	p = path.Clean("/" + p)
+	ps := strings.Split(p[1:], "/")
+	if len(ps) < n {
+		return p, ""
+	}
+	head = path.Clean("/" + strings.Join(ps[:n], "/"))
+	tail = path.Clean("/" + strings.Join(ps[n:], "/"))
+	return head, tail
+}
+`
+
+	tplRouterTest = `package {{ .PackageName }}
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+{{- if .Imports }}
+{{ range .Imports }}
+	{{ . }}
+{{- end }}
+{{- end }}
+)
+
+func TestRouter(t *testing.T) {
+	tests := []struct {
+		method  string
+		path    string
+		pattern string
+		// fallback is the handler expected to respond if no route matches the request.
+		fallback http.HandlerFunc
+	}{
+{{- range .Cases }}
+		{ {{ .Method }}, {{ printf "%q" .Path }}, {{ printf "%q" .Pattern }}, {{ if .Fallback }}{{ .Fallback }}{{ else }}nil{{ end }} },
+{{- end }}
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			t.Parallel()
+			req, route := WithMatchedRoute(httptest.NewRequest(tt.method, tt.path, nil))
+			rec := httptest.NewRecorder()
+			NewRouter().ServeHTTP(rec, req)
+			if route.Pattern != tt.pattern {
+				t.Errorf("%s %s matched %q, want %q", tt.method, tt.path, route.Pattern, tt.pattern)
+			}
+			if tt.fallback == nil {
+				return
+			}
+			want := httptest.NewRecorder()
+			tt.fallback(want, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != want.Code {
+				t.Errorf("%s %s responded with %d, want %d", tt.method, tt.path, rec.Code, want.Code)
+			}
+		})
+	}
+}
+`
+)
+
+// builtinTpls are the templates used unless they are overridden by Options.Templates.
+var builtinTpls = map[string]string{
+	"HeadMsg":            tplHeadMsg,
+	"Package":            tplPackage,
+	"Import":             tplImport,
+	"ImportSpec":         tplImpl,
+	"ClosingBracket":     tplClosingBracket,
+	"Router":             tplRouter,
+	"Handlers":           tplHandlers,
+	"ServeMux":           tplServeMux,
+	"HandlerFunc":        tplHandlerFunc,
+	"SeparatePath":       tplSeparatePath,
+	"Switch":             tplSwitch,
+	"Case":               tplCase,
+	"Default":            tplDefault,
+	"Impl":               tplImpl,
+	"HandlerCall":        tplHandlerCall,
+	"ClosingCurlyBraces": tplClosingCurlyBraces,
+	"If":                 tplIf,
+	"Else":               tplElse,
+	"SeparatePathFunc":   tplSeparatePathFunc,
+	"ParamsFunc":         tplParamsFunc,
+	"RecoverFunc":        tplRecoverFunc,
+	"RouteTable":         tplRouteTable,
+	"RouteFunc":          tplRouteFunc,
+	"MetricsFunc":        tplMetricsFunc,
+	"MountFunc":          tplMountFunc,
+	"NotFoundFunc":       tplNotFoundFunc,
+	"DefaultHandlers":    tplDefaultHandlers,
+	"ParamsStruct":       tplParamsStruct,
+	"ParamsFile":         tplParamsFile,
+	"RouterTest":         tplRouterTest,
+	"Stubs":              tplStubs,
+	"Client":             tplClient,
+	"StarterRouter":      tplStarterRouter,
+}
+
+// TemplateNames returns the names of the templates accepted by LoadTemplates and Options.Templates in sorted order.
+func TemplateNames() []string {
+	names := make([]string, 0, len(builtinTpls))
+	for name := range builtinTpls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BuiltinTemplate returns the built-in template of the name, which is the starting point to override it.
+func BuiltinTemplate(name string) (string, bool) {
+	tpl, ok := builtinTpls[name]
+	return tpl, ok
+}
+
+// templateExt is the extension of the template files loaded by LoadTemplates.
+const templateExt = ".tmpl"
+
+// LoadTemplates loads the templates overriding the built-in ones from the files named "<name>.tmpl" in dir.
+// The templates are text/template, and the names and the data passed to them are as follows.
+// TemplateNames returns the names, and BuiltinTemplate returns the built-in template of each name.
+//
+//	HeadMsg            struct{ Constraint, Generate string }: the header, where Constraint is the build constraint
+//	                   required in addition to !stdrouter such as "go1.22" or "", and Generate is the command of the
+//	                   go:generate directive with the flags of the generation, which is empty for the test file
+//	Package            string: the package name
+//	Import             nil: the beginning of the import declaration
+//	ImportSpec         string: the import spec such as "net/http" quoted, named if the router file names it differently
+//	ClosingBracket     nil: the end of the import declaration
+//	Router             struct{ Name string; Recover bool; Mounts []struct{ Prefix, Handler string }; Interface bool }:
+//	                   the router, where Name is the name of the router variable in the router file, Recover reports
+//	                   whether HandlePanic is declared, Mounts are the handlers mounted with Mount, the longest prefix
+//	                   first, and Interface reports whether NewRouter takes the Handlers (Options.Interface)
+//	Handlers           []struct{ Name string; Routes []string; Params []struct{ Name, Type string } }:
+//	                   the interface of the handlers of the routes (Options.Interface)
+//	ServeMux           struct{ Routes []struct{ Pattern, Call string }; MethodNotAllowed string;
+//	                   NotAllowedPatterns []string; ScopedNotAllowed []struct{ Call string; Patterns []string };
+//	                   NotFound string; Recover bool; Mounts []struct{ Prefix, Handler string }; Interface bool }:
+//	                   NewRouter of TargetServeMux, where ScopedNotAllowed are the MethodNotAllowed handlers of the groups
+//	HandlerFunc        struct{ FuncName string; PathParams []string; Interface bool }: the beginning of the function
+//	                   handling a path param, which takes the Handlers first with Options.Interface
+//	SeparatePath       struct{ Base string; Num int; Tail string }: the statement separating the path
+//	Switch             string: the expression of the switch statement
+//	Case               string: the expression of the case clause
+//	Default            nil: the default clause
+//	HandlerCall        struct{ Func, Args, Handler, Method, Pattern, Name string }: the statement calling the handler
+//	Impl               string: the statement calling the function handling a path param
+//	ClosingCurlyBraces nil: the end of the block
+//	If                 string: the condition of the if statement
+//	Else               nil: the else clause
+//	SeparatePathFunc   nil: the helper function SeparatePath
+//	ParamsFunc         nil: the helper functions Param and Params (ParamsContext)
+//	RouteTable         []struct{ Name, Method, Pattern, Handler string; Params []string }:
+//	                   the table of the routes Routes and the function LookupRoute (Options.RouteTable)
+//	RouteFunc          nil: the helper functions RoutePattern, RouteName and WithMatchedRoute
+//	                   (Options.RouteContext or Options.Testable)
+//	MetricsFunc        struct{ Name string; Keys []string }: the metrics published as Name and the helper function track
+//	                   (Options.Metrics)
+//	MountFunc          struct{ Metrics bool }: the helper function serveMount for the handlers mounted with Mount,
+//	                   which counts the requests to the mounts with Options.Metrics
+//	DefaultHandlers    []struct{ Name, Declaration, Code, Status, ContentType, Body string }: the built-in NotFound and
+//	                   MethodNotAllowed handlers not declared in the router file, where Code is the constant of the status
+//	                   code, Status is the status line and ContentType is empty for the plain text written with http.Error
+//	NotFoundFunc       struct{ Scopes []struct{ Prefix, Call string }; Default string }: the helper function
+//	                   handleNotFound calling the NotFound handler of the group nearest to the path
+//	RecoverFunc        string: the helper function recoverPanic, where the data is the statement calling the panic handler
+//	ParamsStruct       []struct{ Name, Handler string; Fields []struct{ Name, Param string } }:
+//	                   the structs of the path params (ParamsStruct)
+//	ParamsFile         string: the beginning of the file declaring the structs in the package of the handlers
+//	RouterTest         struct{ PackageName string; Imports []string; Cases []struct{ Method, Path, Pattern,
+//	                   Fallback string } }: the body of the test file, where Imports are the import specs,
+//	                   Pattern is the route expected to match the request and Fallback is the handler expected
+//	                   to respond if no route matches it
+//	Stubs              []struct{ Name, Handles string; Params []struct{ Name, Type string } }: the stubs of the
+//	                   handlers generated by GenerateStubs, where Handles are the routes or the fallbacks handled by them
+//	Client             struct{ Package string; Methods []struct{ Name, Method, Pattern, Params, Path string;
+//	                   Doc []string } }: the file of the client generated by GenerateClient, where Params is the
+//	                   parameter list of the path params such as "userID, postID string", Path is the Go expression
+//	                   of the path built from them and Doc are the lines of the doc comment of the route
+//	StarterRouter      string: the starter router file generated by GenerateStarter, where the data is the package name
+//
+// The files of the other names are reported as an error.
+func LoadTemplates(dir string) (map[string]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory -> %w", err)
+	}
+	tpls := make(map[string]string)
+	for _, fi := range files {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != templateExt {
+			continue
+		}
+		name := strings.TrimSuffix(fi.Name(), templateExt)
+		if _, ok := builtinTpls[name]; !ok {
+			return nil, fmt.Errorf("unknown template: %s", fi.Name())
+		}
+		b, err := ioutil.ReadFile(filepath.Join(dir, fi.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file -> %w", err)
+		}
+		tpls[name] = string(b)
+	}
+	return tpls, nil
+}
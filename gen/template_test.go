@@ -0,0 +1,74 @@
+package gen
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "load templates and ignore the other files",
+			files: map[string]string{
+				"Router.tmpl":      "type Router struct{}\n",
+				"HandlerCall.tmpl": "{{ .Func }}({{ .Args }})\n",
+				"README.md":        "templates\n",
+			},
+			want: map[string]string{
+				"Router":      "type Router struct{}\n",
+				"HandlerCall": "{{ .Func }}({{ .Args }})\n",
+			},
+		},
+		{
+			name:    "unknown template",
+			files:   map[string]string{"Routers.tmpl": "type Router struct{}\n"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "stdrouter")
+			if err != nil {
+				t.Fatalf("ioutil.TempDir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+			for name, content := range tt.files {
+				if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatalf("ioutil.WriteFile: %v", err)
+				}
+			}
+
+			got, err := LoadTemplates(dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadTemplates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadTemplates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateNames(t *testing.T) {
+	names := TemplateNames()
+	if len(names) != len(builtinTpls) || !sort.StringsAreSorted(names) {
+		t.Errorf("TemplateNames() = %v, want the %d names in sorted order", names, len(builtinTpls))
+	}
+	for _, name := range names {
+		if tpl, ok := BuiltinTemplate(name); !ok || tpl == "" {
+			t.Errorf("BuiltinTemplate(%q) = %q, %v, want the built-in template", name, tpl, ok)
+		}
+	}
+	if _, ok := BuiltinTemplate("Routers"); ok {
+		t.Error("BuiltinTemplate(\"Routers\") = true, want false for the unknown name")
+	}
+}
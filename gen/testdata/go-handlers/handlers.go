@@ -0,0 +1,6 @@
+// Package handlers is imported by router_imports.go from the directory named differently from the package.
+package handlers
+
+import "net/http"
+
+func GetPosts(w http.ResponseWriter, r *http.Request) {}
@@ -0,0 +1,18 @@
+//+build stdrouter
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+	"github.com/tetsuzawa/stdrouter/_example/handler"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, handler.GetRoot)
+	r.HandleFunc("/api/users/:user_id", http.MethodGet, handler.GetUser)
+	r.HandleFunc("/api/users/:user_id", http.MethodDelete, handler.DeleteUser)
+	return r
+}
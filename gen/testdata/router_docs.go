@@ -0,0 +1,26 @@
+//+build stdrouter
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+	"github.com/tetsuzawa/stdrouter/_example/handler"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, handler.GetRoot) // GetRoot replies with the name of the service.
+	// GetUsers returns the users.
+	// They are sorted by the IDs.
+	r.HandleFunc("/api/users", http.MethodGet, handler.GetUsers)
+	r.HandleFunc("/api/users/:user_id", http.MethodGet, handler.GetUser).Name("get-user")
+	// GetPost returns the post of the user.
+	r.HandleFunc("/api/users/:user_id/posts/:post_id", http.MethodGet, handler.GetPost)
+	r.HandleFunc("/api/products", http.MethodPost, handler.CreateProducts)
+	r.Mount("/static", http.FileServer(http.Dir("static")))
+	r.HandleNotFound(handler.NotFoundHandler)
+	r.HandleMethodNotAllowed(handler.MethodNotAllowedHandler)
+	return r
+}
@@ -0,0 +1,14 @@
+//+build stdrouter
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	return r
+}
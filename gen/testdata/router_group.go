@@ -0,0 +1,26 @@
+//+build stdrouter
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+	"github.com/tetsuzawa/stdrouter/_example/handler"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, handler.GetRoot)
+	api := r.Group("/api")
+	api.HandleFunc("/users", http.MethodGet, handler.GetUsers)
+	api.HandleNotFound(handler.APINotFoundHandler)
+	api.HandleMethodNotAllowed(handler.APIMethodNotAllowedHandler)
+	user := api.Group("/users/:user_id")
+	user.HandleFunc("/", http.MethodGet, handler.GetUser)
+	user.HandleFunc("/posts", http.MethodGet, handler.GetPosts)
+	user.HandleNotFound(handler.UserNotFoundHandler)
+	r.HandleNotFound(handler.NotFoundHandler)
+	r.HandleMethodNotAllowed(handler.MethodNotAllowedHandler)
+	return r
+}
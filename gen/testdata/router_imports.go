@@ -0,0 +1,22 @@
+//+build stdrouter
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+	h "github.com/tetsuzawa/stdrouter/_example/handler"
+	"github.com/tetsuzawa/stdrouter/gen/testdata/go-handlers"
+
+	"example.com/admin/v2"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/users", http.MethodGet, h.GetUsers)
+	r.HandleFunc("/posts", http.MethodGet, handlers.GetPosts)
+	r.Mount("/admin", admin.NewRouter())
+	r.HandleNotFound(h.NotFoundHandler)
+	return r
+}
@@ -0,0 +1,23 @@
+//+build stdrouter
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+	"github.com/tetsuzawa/stdrouter/_example/handler"
+
+	"example.com/admin"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/", http.MethodGet, handler.GetRoot)
+	r.HandleFunc("/api/users/:user_id", http.MethodGet, handler.GetUser)
+	r.Mount("/admin", admin.NewRouter())
+	r.Mount("/admin/static", http.FileServer(http.Dir("static")))
+	r.HandleNotFound(handler.NotFoundHandler)
+	r.HandleMethodNotAllowed(handler.MethodNotAllowedHandler)
+	return r
+}
@@ -0,0 +1,36 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Formats of the tree of the routes.
+const (
+	// TreeFormatText writes the tree indented with ASCII lines.
+	TreeFormatText = "text"
+	// TreeFormatDot writes the tree as a graph in the DOT language of Graphviz.
+	TreeFormatDot = "dot"
+)
+
+// GenerateTree returns the tree of the path segments built from the routes in the spec as the generator does, in the format.
+// The path params are marked with ":", and the nodes are labeled with the methods and the handlers registered to them.
+func GenerateTree(spec *RouterSpec, format string) ([]byte, error) {
+	cfg, err := newConfig(spec)
+	if err != nil {
+		return nil, fmt.Errorf("newConfig -> %w", err)
+	}
+	var b bytes.Buffer
+	switch format {
+	case "", TreeFormatText:
+		err = cfg.Node.FprintTree(&b)
+	case TreeFormatDot:
+		err = cfg.Node.FprintDot(&b)
+	default:
+		return nil, fmt.Errorf("unknown tree format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write the tree: %w", err)
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,48 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateTree(t *testing.T) {
+	spec := &RouterSpec{
+		Routes: []Route{
+			{Method: "GET", Pattern: "/users", Handler: Handler{Package: "handler", Func: "GetUsers"}},
+			{Method: "GET", Pattern: "/users/:user_id", Handler: Handler{Package: "handler", Func: "GetUser"}},
+		},
+	}
+	tests := []struct {
+		name    string
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "text",
+			format: TreeFormatText,
+			want:   "/\n`-- users [GET handler.GetUsers]\n    `-- :user_id [GET handler.GetUser]\n",
+		},
+		{
+			name:   "dot",
+			format: TreeFormatDot,
+			want:   "digraph routes {",
+		},
+		{
+			name:    "unknown format",
+			format:  "svg",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateTree(spec, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateTree() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.HasPrefix(string(got), tt.want) {
+				t.Errorf("GenerateTree() = %q, want the prefix %q", got, tt.want)
+			}
+		})
+	}
+}
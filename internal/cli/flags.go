@@ -0,0 +1,58 @@
+// Package cli defines the flags of the generation shared by the stdrouter command and the analyzer.
+package cli
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+// RegisterFlags defines the flags of the options except Templates and Directive in fs,
+// so that the tools checking the generated code accept the same flags as the stdrouter command.
+func RegisterFlags(fs *flag.FlagSet, opts *gen.Options) {
+	fs.StringVar(&opts.Target, "target", gen.TargetRouter, "generation target: router or servemux (Go 1.22 net/http.ServeMux)")
+	fs.BoolVar(&opts.Testable, "tests", false, "generate the test of every route to <output>_test.go")
+	fs.StringVar(&opts.Params, "params", gen.ParamsPositional, "way to pass the path params to the handlers: positional, context or struct")
+	fs.BoolVar(&opts.PathValue, "pathvalue", false, "set the path params with Request.SetPathValue before calling the handlers (Go 1.22)")
+	fs.StringVar(&opts.GoVersion, "go", "", "minimum Go version of the generated code written to the build constraint, e.g. 1.22")
+	fs.BoolVar(&opts.RouteTable, "routes", false, "generate the table of the routes Routes and the function LookupRoute")
+	fs.BoolVar(&opts.RouteContext, "routecontext", false, "store the matched route in the request context for RoutePattern and RouteName")
+	fs.BoolVar(&opts.Metrics, "metrics", false, "count the requests, errors and latency of every route and publish them through expvar")
+	fs.StringVar(&opts.MetricsName, "metricsname", "", "name of the metrics of the router in the expvar \"stdrouter\" (default the package name)")
+	fs.BoolVar(&opts.Interface, "interface", false, "generate the interface Handlers of the route handlers taken by NewRouter")
+	fs.StringVar(&opts.ErrorFormat, "errorformat", gen.ErrorFormatText, "format of the built-in NotFound and MethodNotAllowed handlers: text, json or problem (RFC 7807)")
+}
+
+// Directive returns the command of the go:generate directive regenerating the router with the options,
+// such as "stdrouter -routecontext -tests". The flags are the ones of RegisterFlags which differ from the defaults,
+// followed by args, the other arguments of stdrouter such as "-i=api.go".
+func Directive(opts gen.Options, args []string) string {
+	fs := flag.NewFlagSet("stdrouter", flag.ContinueOnError)
+	o := new(gen.Options)
+	RegisterFlags(fs, o)
+	// the flags read the options through the pointers to the fields
+	*o = opts
+	var flags []string
+	fs.VisitAll(func(f *flag.Flag) {
+		v := f.Value.String()
+		if v == "" || v == f.DefValue {
+			return
+		}
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			flags = append(flags, "-"+f.Name)
+			return
+		}
+		flags = append(flags, "-"+f.Name+"="+v)
+	})
+	cmd := "stdrouter"
+	for _, arg := range append(flags, args...) {
+		// go generate splits the command by spaces except in the quoted strings
+		if strings.ContainsAny(arg, " \t\"") {
+			arg = strconv.Quote(arg)
+		}
+		cmd += " " + arg
+	}
+	return cmd
+}
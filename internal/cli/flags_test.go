@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+)
+
+func TestDirective(t *testing.T) {
+	tests := []struct {
+		name string
+		opts gen.Options
+		args []string
+		want string
+	}{
+		{
+			name: "defaults",
+			opts: gen.Options{Target: gen.TargetRouter, Params: gen.ParamsPositional, ErrorFormat: gen.ErrorFormatText},
+			want: "stdrouter",
+		},
+		{
+			name: "zero options",
+			opts: gen.Options{},
+			want: "stdrouter",
+		},
+		{
+			name: "flags",
+			opts: gen.Options{Target: gen.TargetServeMux, Testable: true, Params: gen.ParamsStruct, GoVersion: "1.23", RouteContext: true},
+			want: "stdrouter -go=1.23 -params=struct -routecontext -target=servemux -tests",
+		},
+		{
+			name: "args",
+			opts: gen.Options{Metrics: true},
+			args: []string{"-i=api.go", "-templates=my templates"},
+			want: `stdrouter -metrics -i=api.go "-templates=my templates"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Directive(tt.opts, tt.args); got != tt.want {
+				t.Errorf("Directive() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
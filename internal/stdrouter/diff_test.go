@@ -0,0 +1,165 @@
+package stdrouter
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	type args struct {
+		a string
+		b string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "same contents",
+			args: args{a: "a\nb\n", b: "a\nb\n"},
+			want: "",
+		},
+		{
+			name: "changed line",
+			args: args{a: "a\nb\nc\n", b: "a\nx\nc\n"},
+			want: `--- old
++++ new
+@@ -1,3 +1,3 @@
+ a
+-b
++x
+ c
+`,
+		},
+		{
+			name: "added lines to empty",
+			args: args{a: "", b: "a\nb\n"},
+			want: `--- old
++++ new
+@@ -0,0 +1,2 @@
++a
++b
+`,
+		},
+		{
+			name: "changes far apart are separated into hunks",
+			args: args{a: "x\n1\n2\n3\n4\n5\n6\n7\n8\n9\n", b: "1\n2\n3\n4\n5\n6\n7\n8\n9\ny\n"},
+			want: `--- old
++++ new
+@@ -1,4 +1,3 @@
+-x
+ 1
+ 2
+ 3
+@@ -8,3 +7,4 @@
+ 7
+ 8
+ 9
++y
+`,
+		},
+		{
+			name: "removed newline at end of file",
+			args: args{a: "a\nb\n", b: "a\nb"},
+			want: `--- old
++++ new
+@@ -1,2 +1,2 @@
+ a
+-b
++b
+\ No newline at end of file
+`,
+		},
+		{
+			name: "added line after line without newline",
+			args: args{a: "a\nb", b: "a\nb\nc\n"},
+			want: `--- old
++++ new
+@@ -1,2 +1,3 @@
+ a
+-b
+\ No newline at end of file
++b
++c
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := UnifiedDiff("old", "new", []byte(tt.args.a), []byte(tt.args.b)); got != tt.want {
+				t.Errorf("UnifiedDiff() = \n%v, want \n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditScript(t *testing.T) {
+	// lcsLen returns the length of the longest common subsequence of a and b.
+	lcsLen := func(a, b []string) int {
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				switch {
+				case a[i] == b[j]:
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				case lcs[i+1][j] >= lcs[i][j+1]:
+					lcs[i][j] = lcs[i+1][j]
+				default:
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		return lcs[0][0]
+	}
+	lines := func(r *rand.Rand) []string {
+		l := make([]string, r.Intn(30))
+		for i := range l {
+			l[i] = string(rune('a' + r.Intn(4)))
+		}
+		return l
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a, b := lines(r), lines(r)
+		ops := editScript(a, b)
+		var gotA, gotB []string
+		edits := 0
+		for _, o := range ops {
+			if o.kind != '+' {
+				gotA = append(gotA, o.line)
+			}
+			if o.kind != '-' {
+				gotB = append(gotB, o.line)
+			}
+			if o.kind != ' ' {
+				edits++
+			}
+		}
+		if strings.Join(gotA, "") != strings.Join(a, "") || strings.Join(gotB, "") != strings.Join(b, "") {
+			t.Fatalf("editScript(%q, %q) = %v, want the operations converting a to b", a, b, ops)
+		}
+		if want := len(a) + len(b) - 2*lcsLen(a, b); edits != want {
+			t.Fatalf("editScript(%q, %q) = %v, %d edits, want %d", a, b, ops, edits, want)
+		}
+	}
+}
+
+func TestUnifiedDiff_large(t *testing.T) {
+	// the files replaced entirely take the longest edit script,
+	// for which the table of the longest common subsequence took 800 MB
+	var a, b strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&a, "a%d\n", i)
+		fmt.Fprintf(&b, "b%d\n", i)
+	}
+	got := UnifiedDiff("old", "new", []byte(a.String()), []byte(b.String()))
+	if !strings.HasPrefix(got, "--- old\n+++ new\n@@ -1,10000 +1,10000 @@\n-a0\n") {
+		t.Errorf("UnifiedDiff() = %.100s..., want one hunk replacing every line", got)
+	}
+}
@@ -0,0 +1,46 @@
+package stdrouter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// InModule reports whether the package of the import path is in the module of the path modPath.
+func InModule(importPath, modPath string) bool {
+	return importPath == modPath || strings.HasPrefix(importPath, modPath+"/")
+}
+
+// ModuleDir returns the directory of the package of the import path in the module of the root directory and the path modPath.
+func ModuleDir(root, modPath, importPath string) string {
+	return filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(importPath, modPath)))
+}
+
+// FindModule returns the root directory and the path of the module containing dir.
+func FindModule(dir string) (root, modPath string, err error) {
+	root, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	for {
+		b, err := ioutil.ReadFile(filepath.Join(root, "go.mod"))
+		if err == nil {
+			for _, line := range strings.Split(string(b), "\n") {
+				if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+					return root, strings.Trim(fields[1], `"`), nil
+				}
+			}
+			return "", "", fmt.Errorf("module path is not found in %s", filepath.Join(root, "go.mod"))
+		}
+		if !os.IsNotExist(err) {
+			return "", "", fmt.Errorf("failed to read go.mod: %w", err)
+		}
+		parent := filepath.Dir(root)
+		if parent == root {
+			return "", "", fmt.Errorf("go.mod is not found in %s or its parents", dir)
+		}
+		root = parent
+	}
+}
@@ -2,6 +2,7 @@ package stdrouter
 
 import (
 	"fmt"
+	"io"
 	"path"
 	"strings"
 )
@@ -113,11 +114,97 @@ func (n *Node) Add(p string, httpMethod string, handlerFunc HandlerFunc) error {
 func (n *Node) Print() {
 	Walk(n, func(node *Node) bool {
 		fmt.Printf("Depth: %v, Endpoint: %v, IsPathPram: %v, Methods: %v, hasParent: %v, numChildren: %v\n",
-			node.Depth, node.Endpoint, node.IsPathParam, node.Methods, node.Parent == nil, len(node.Children))
+			node.Depth, node.Endpoint, node.IsPathParam, node.Methods, node.Parent != nil, len(node.Children))
 		return true
 	})
 }
 
+// FprintTree writes the tree of the nodes indented with ASCII lines in the order of the children.
+// The path params are prefixed with ":" and followed by the methods and the handlers of the nodes.
+func (n *Node) FprintTree(w io.Writer) error {
+	var b strings.Builder
+	var print func(node *Node, indent string)
+	print = func(node *Node, indent string) {
+		for i, child := range node.Children {
+			branch, next := "|-- ", "|   "
+			if i == len(node.Children)-1 {
+				branch, next = "`-- ", "    "
+			}
+			b.WriteString(indent + branch + nodeLine(child) + "\n")
+			print(child, indent+next)
+		}
+	}
+	b.WriteString(nodeLine(n) + "\n")
+	print(n, "")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// FprintDot writes the tree of the nodes as a graph in the DOT language of Graphviz.
+// The path params are drawn as ellipses and the other nodes as boxes, labeled with the methods and the handlers.
+func (n *Node) FprintDot(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph routes {\n\tnode [shape=box];\n")
+	// the nodes are numbered in the preorder.
+	id := 0
+	var print func(node *Node)
+	print = func(node *Node) {
+		nodeID := id
+		id++
+		label := strings.Join(append([]string{nodeLabel(node)}, nodeMethods(node)...), "\n")
+		attrs := fmt.Sprintf("label=%q", label)
+		if node.IsPathParam {
+			attrs += ", shape=ellipse"
+		}
+		fmt.Fprintf(&b, "\tn%d [%s];\n", nodeID, attrs)
+		for _, child := range node.Children {
+			fmt.Fprintf(&b, "\tn%d -> n%d;\n", nodeID, id)
+			print(child)
+		}
+	}
+	print(n)
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// nodeLine returns the label of the node followed by the methods and the handlers in brackets.
+func nodeLine(node *Node) string {
+	methods := nodeMethods(node)
+	if len(methods) == 0 {
+		return nodeLabel(node)
+	}
+	return nodeLabel(node) + " [" + strings.Join(methods, ", ") + "]"
+}
+
+// nodeLabel returns the endpoint of the node as written in the pattern. The root is "/".
+func nodeLabel(node *Node) string {
+	switch {
+	case node.Parent == nil && node.Depth == 0:
+		return "/"
+	case node.IsPathParam:
+		return ":" + node.Endpoint
+	default:
+		return node.Endpoint
+	}
+}
+
+// nodeMethods returns the methods of the node with the handlers such as "GET handler.GetUser" in the canonical order.
+func nodeMethods(node *Node) []string {
+	var methods []string
+	for _, m := range HTTPMethods {
+		m = strings.ToUpper(strings.TrimPrefix(m, "Method"))
+		if h, ok := node.Methods[m]; ok {
+			name := h.Func
+			if h.Package != "" {
+				name = h.Package + "." + h.Func
+			}
+			methods = append(methods, m+" "+name)
+		}
+	}
+	return methods
+}
+
 // Walk performs a BFS (breadth-first search) on the tree structure of nodes
 // and executes the argument function on each node.
 // If the return value is false, the search ends.
@@ -157,3 +244,31 @@ func BuildBasePath(node *Node) (p string) {
 	}
 	return p
 }
+
+// BuildPath builds the route pattern from root to argument node.
+// Path param nodes are written with the ":" prefix as in the router file.
+func BuildPath(node *Node) string {
+	p := ""
+	for n := node; n.Parent != nil; n = n.Parent {
+		endpoint := n.Endpoint
+		if n.IsPathParam {
+			endpoint = ":" + endpoint
+		}
+		p = "/" + endpoint + p
+	}
+	if p == "" {
+		return "/"
+	}
+	return p
+}
+
+// PathParams returns the names of path params from root to argument node.
+func PathParams(node *Node) []string {
+	var params []string
+	for n := node; n.Parent != nil; n = n.Parent {
+		if n.IsPathParam {
+			params = append([]string{n.Endpoint}, params...)
+		}
+	}
+	return params
+}
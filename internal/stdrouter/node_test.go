@@ -896,3 +896,116 @@ func TestBuildBasePath(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildPath(t *testing.T) {
+	rootNode := &Node{}
+	for _, p := range []string{"/", "/api/users", "/api/users/:user_id/posts/:post_id"} {
+		if err := rootNode.Add(p, "Get", HandlerFunc{"handler", "Handle"}); err != nil {
+			t.Fatalf("Node.Add: %v", err)
+		}
+	}
+	users := rootNode.Children[0].Children[0]
+	postID := users.Children[0].Children[0].Children[0]
+
+	type args struct {
+		node *Node
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       string
+		wantParams []string
+	}{
+		{
+			name:       "root node",
+			args:       args{node: rootNode},
+			want:       "/",
+			wantParams: nil,
+		},
+		{
+			name:       "node without path param",
+			args:       args{node: users},
+			want:       "/api/users",
+			wantParams: nil,
+		},
+		{
+			name:       "node with path params",
+			args:       args{node: postID},
+			want:       "/api/users/:user_id/posts/:post_id",
+			wantParams: []string{"user_id", "post_id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuildPath(tt.args.node); got != tt.want {
+				t.Errorf("BuildPath() = %v, want %v", got, tt.want)
+			}
+			if got := PathParams(tt.args.node); !reflect.DeepEqual(got, tt.wantParams) {
+				t.Errorf("PathParams() = %v, want %v", got, tt.wantParams)
+			}
+		})
+	}
+}
+
+// newTestTree returns the tree of the routes added in the order.
+func newTestTree(t *testing.T) *Node {
+	root := new(Node)
+	routes := []struct {
+		p, method, handler string
+	}{
+		{"/", "GET", "GetRoot"},
+		{"/api/users", "GET", "GetUsers"},
+		{"/api/users", "POST", "CreateUser"},
+		{"/api/users/:user_id", "DELETE", "DeleteUser"},
+		{"/api/users/:user_id", "GET", "GetUser"},
+		{"/api/users/:user_id/posts", "GET", "GetPosts"},
+		{"/api/products", "GET", "GetProducts"},
+	}
+	for _, rt := range routes {
+		if err := root.Add(rt.p, rt.method, HandlerFunc{Package: "handler", Func: rt.handler}); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+	return root
+}
+
+func TestNode_FprintTree(t *testing.T) {
+	want := "/ [GET handler.GetRoot]\n" +
+		"`-- api\n" +
+		"    |-- users [GET handler.GetUsers, POST handler.CreateUser]\n" +
+		"    |   `-- :user_id [GET handler.GetUser, DELETE handler.DeleteUser]\n" +
+		"    |       `-- posts [GET handler.GetPosts]\n" +
+		"    `-- products [GET handler.GetProducts]\n"
+	var b bytes.Buffer
+	if err := newTestTree(t).FprintTree(&b); err != nil {
+		t.Fatalf("FprintTree: %v", err)
+	}
+	if got := b.String(); got != want {
+		t.Errorf("FprintTree() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestNode_FprintDot(t *testing.T) {
+	want := `digraph routes {
+	node [shape=box];
+	n0 [label="/\nGET handler.GetRoot"];
+	n0 -> n1;
+	n1 [label="api"];
+	n1 -> n2;
+	n2 [label="users\nGET handler.GetUsers\nPOST handler.CreateUser"];
+	n2 -> n3;
+	n3 [label=":user_id\nGET handler.GetUser\nDELETE handler.DeleteUser", shape=ellipse];
+	n3 -> n4;
+	n4 [label="posts\nGET handler.GetPosts"];
+	n1 -> n5;
+	n5 [label="products\nGET handler.GetProducts"];
+}
+`
+	var b bytes.Buffer
+	if err := newTestTree(t).FprintDot(&b); err != nil {
+		t.Fatalf("FprintDot: %v", err)
+	}
+	if got := b.String(); got != want {
+		t.Errorf("FprintDot() =\n%s\nwant:\n%s", got, want)
+	}
+}
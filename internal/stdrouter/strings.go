@@ -34,3 +34,29 @@ func ToLowerFirstLetter(s string) string {
 	s = string(a)
 	return s
 }
+
+// commonInitialisms are the initialisms written in all capitals in Go names.
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"LHS": true, "QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true,
+	"SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true,
+	"UID": true, "UUID": true, "URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true,
+	"XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// SnakeToGoName converts string from snake_case to the exported Go name with the common initialisms, e.g. user_id to UserID.
+func SnakeToGoName(snake_case string) string {
+	var name string
+	for _, word := range strings.Split(snake_case, "_") {
+		if word == "" {
+			continue
+		}
+		if u := strings.ToUpper(word); commonInitialisms[u] {
+			name += u
+			continue
+		}
+		name += strings.ToUpper(word[:1]) + word[1:]
+	}
+	return name
+}
@@ -69,3 +69,42 @@ func TestToLowerFirstLetter(t *testing.T) {
 		})
 	}
 }
+
+func TestSnakeToGoName(t *testing.T) {
+	type args struct {
+		snake_case string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "convert snake_case to CamelCase",
+			args: args{"post_title"},
+			want: "PostTitle",
+		},
+		{
+			name: "write initialisms in all capitals",
+			args: args{"user_id"},
+			want: "UserID",
+		},
+		{
+			name: "initialism at the beginning",
+			args: args{"url_path"},
+			want: "URLPath",
+		},
+		{
+			name: "keep CamelCase",
+			args: args{"userName"},
+			want: "UserName",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SnakeToGoName(tt.args.snake_case); got != tt.want {
+				t.Errorf("SnakeToGoName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
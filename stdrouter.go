@@ -6,12 +6,25 @@ package stdrouter
 
 import "net/http"
 
-
 type Router struct{}
 
 func NewRouter() Router { return Router{} }
 
-func (router Router) ServeHTTP(w http.ResponseWriter, r *http.Request)                         {}
-func (router Router) HandleFunc(path interface{}, method interface{}, handlerFunc interface{}) {}
-func (router Router) HandleNotFound(handlerFunc interface{})                                   {}
-func (router Router) HandleMethodNotAllowed(handlerFunc interface{})                           {}
+func (router Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+func (router Router) HandleFunc(path interface{}, method interface{}, handlerFunc interface{}) Route {
+	return Route{}
+}
+func (router Router) HandleNotFound(handlerFunc interface{})         {}
+func (router Router) HandleMethodNotAllowed(handlerFunc interface{}) {}
+func (router Router) HandlePanic(handlerFunc interface{})            {}
+func (router Router) Mount(prefix string, handler http.Handler)      {}
+func (router Router) Group(prefix string) Router                     { return Router{} }
+
+// NotFound and MethodNotAllowed are declared with HandleNotFound and HandleMethodNotAllowed
+// to use the built-in handlers, which reply with 404 and 405 with the Allow header in the format of -errorformat.
+func NotFound(w http.ResponseWriter, r *http.Request)         {}
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {}
+
+type Route struct{}
+
+func (route Route) Name(name string) {}
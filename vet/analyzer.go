@@ -0,0 +1,500 @@
+// Package vet provides the analyzer checking the router files of stdrouter,
+// which runs in go vet with -vettool, in the stdroutervet command and in the other drivers of go/analysis.
+package vet
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/tetsuzawa/stdrouter/gen"
+	"github.com/tetsuzawa/stdrouter/internal/cli"
+)
+
+const doc = `check the router files of stdrouter
+
+The stdrouter analyzer reports the problems of the router file (router.go by default) in the package:
+the routes declared twice or with the path params named differently at the same position,
+the handlers whose parameters do not match the path params of the routes, the unknown HTTP methods,
+the missing NotFound and MethodNotAllowed handlers, and the generated router which is not up to date with the router file.
+Pass the flags of the generation as to the stdrouter command (e.g. -stdrouter.params=context)
+to check the generated files and the handlers.`
+
+// Analyzer checks the router files of stdrouter.
+var Analyzer = &analysis.Analyzer{
+	Name: "stdrouter",
+	Doc:  doc,
+	Run:  run,
+}
+
+var (
+	routerFileName string
+	outputFileName string
+	templateDir    string
+	opts           gen.Options
+)
+
+func init() {
+	Analyzer.Flags.StringVar(&routerFileName, "i", "router.go", "router config file name (comma-separated for multiple files)")
+	Analyzer.Flags.StringVar(&outputFileName, "o", "router_gen.go", "generated router file name")
+	Analyzer.Flags.StringVar(&templateDir, "templates", "", "directory of the templates (<name>.tmpl) overriding the built-in ones")
+	cli.RegisterFlags(&Analyzer.Flags, &opts)
+}
+
+// stdrouterPath is the import path of the package declaring the router in the router files.
+const stdrouterPath = "github.com/tetsuzawa/stdrouter"
+
+// checker holds the state while checking the router files of a package.
+type checker struct {
+	pass *analysis.Pass
+	// filenames are the names of the router files in the package.
+	filenames []string
+	// files are the syntax trees of the router files by the names.
+	files map[string]*ast.File
+	// srcs are the contents of the router files by the names.
+	srcs map[string][]byte
+	spec *gen.RouterSpec
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c := &checker{pass: pass, files: map[string]*ast.File{}, srcs: map[string][]byte{}}
+	if err := c.loadRouterFiles(); err != nil {
+		return nil, fmt.Errorf("loadRouterFiles -> %w", err)
+	}
+	if len(c.filenames) == 0 {
+		return nil, nil
+	}
+	spec, err := gen.Parse(c.filenames...)
+	var diags gen.Diagnostics
+	if errors.As(err, &diags) {
+		for _, d := range diags {
+			c.reportDiagnostic(d)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gen.Parse -> %w", err)
+	}
+	c.spec = spec
+
+	conflicts := spec.Conflicts()
+	for _, d := range conflicts {
+		c.reportDiagnostic(d)
+	}
+	c.checkHandlers()
+	c.checkFallbacks()
+	if conflicts.HasError() {
+		// the router generated from the conflicting routes is broken anyway.
+		return nil, nil
+	}
+	if err := c.checkGenerated(); err != nil {
+		return nil, fmt.Errorf("checkGenerated -> %w", err)
+	}
+	return nil, nil
+}
+
+// readFile reads the file through the driver if it provides the contents.
+func readFile(pass *analysis.Pass, filename string) ([]byte, error) {
+	if pass.ReadFile != nil {
+		return pass.ReadFile(filename)
+	}
+	return os.ReadFile(filename)
+}
+
+// loadRouterFiles parses the router files of the package into the file set of the pass,
+// so that the problems in them are reported at their positions.
+// The router files are excluded from the build by the build tag, and they are found in the ignored files.
+func (c *checker) loadRouterFiles() error {
+	names := map[string]bool{}
+	for _, name := range strings.Split(routerFileName, ",") {
+		names[filepath.Base(name)] = true
+	}
+	for _, filename := range c.pass.IgnoredFiles {
+		if !names[filepath.Base(filename)] || !strings.HasSuffix(filename, ".go") {
+			continue
+		}
+		src, err := readFile(c.pass, filename)
+		if err != nil {
+			return fmt.Errorf("failed to read router file: %w", err)
+		}
+		// the syntax errors are reported by gen.Parse.
+		f, _ := parser.ParseFile(c.pass.Fset, filename, src, 0)
+		if f == nil || !importsStdrouter(f) {
+			continue
+		}
+		c.filenames = append(c.filenames, filename)
+		c.files[filename] = f
+		c.srcs[filename] = src
+	}
+	return nil
+}
+
+// importsStdrouter reports whether the file imports the package of the router.
+func importsStdrouter(f *ast.File) bool {
+	for _, spec := range f.Imports {
+		if p, err := strconv.Unquote(spec.Path.Value); err == nil && p == stdrouterPath {
+			return true
+		}
+	}
+	return false
+}
+
+// pos returns the position in the file set of the pass of the position reported by the generator.
+// The problems without the position are reported at the package clause of the first router file.
+func (c *checker) pos(p token.Position) token.Pos {
+	if f, ok := c.files[p.Filename]; ok && p.IsValid() {
+		tf := c.pass.Fset.File(f.Package)
+		if p.Offset <= tf.Size() {
+			return tf.Pos(p.Offset)
+		}
+	}
+	return c.files[c.filenames[0]].Package
+}
+
+// reportDiagnostic reports the diagnostic of the generator with the suggested fix if available.
+func (c *checker) reportDiagnostic(d gen.Diagnostic) {
+	pos := c.pos(d.Pos)
+	fixes := c.methodFixes(pos)
+	if d.Severity == gen.SeverityWarning {
+		fixes = append(fixes, c.duplicateFixes(pos)...)
+	}
+	c.pass.Report(analysis.Diagnostic{Pos: pos, Message: d.Message, SuggestedFixes: fixes})
+}
+
+// methodFixes returns the fix replacing the unknown HTTP method at pos with the closest one.
+func (c *checker) methodFixes(pos token.Pos) []analysis.SuggestedFix {
+	var fixes []analysis.SuggestedFix
+	for _, f := range c.files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Pos() != pos {
+				return true
+			}
+			if x, ok := sel.X.(*ast.Ident); !ok || x.Name != "http" {
+				return true
+			}
+			if m := closestMethod(sel.Sel.Name); m != "" && m != sel.Sel.Name {
+				fixes = append(fixes, analysis.SuggestedFix{
+					Message:   "Replace with http." + m,
+					TextEdits: []analysis.TextEdit{{Pos: sel.Sel.Pos(), End: sel.Sel.End(), NewText: []byte(m)}},
+				})
+			}
+			return false
+		})
+	}
+	return fixes
+}
+
+// closestMethod returns the name of the constant of the HTTP method in net/http closest to the name,
+// or the empty string if no method is close enough.
+func closestMethod(name string) string {
+	method := strings.ToUpper(strings.TrimPrefix(name, "Method"))
+	closest, min := "", 3
+	for _, m := range gen.Methods {
+		if d := distance(method, m); d < min {
+			closest, min = m, d
+		}
+	}
+	if closest == "" {
+		return ""
+	}
+	return "Method" + closest[:1] + strings.ToLower(closest[1:])
+}
+
+// distance returns the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func minInt(n int, ns ...int) int {
+	for _, m := range ns {
+		if m < n {
+			n = m
+		}
+	}
+	return n
+}
+
+// duplicateFixes returns the fix removing the route replaced by the duplicate route declared at pos.
+func (c *checker) duplicateFixes(pos token.Pos) []analysis.SuggestedFix {
+	if c.spec == nil {
+		return nil
+	}
+	var dup *gen.Route
+	for i, rt := range c.spec.Routes {
+		if c.pos(rt.Pos) == pos {
+			dup = &c.spec.Routes[i]
+			break
+		}
+	}
+	if dup == nil {
+		return nil
+	}
+	var replaced *gen.Route
+	for i, rt := range c.spec.Routes {
+		if rt.Method == dup.Method && rt.Pattern == dup.Pattern && c.pos(rt.Pos) < pos {
+			replaced = &c.spec.Routes[i]
+		}
+	}
+	if replaced == nil {
+		return nil
+	}
+	stmt := c.enclosingStmt(c.pos(replaced.Pos))
+	if stmt == nil {
+		return nil
+	}
+	tf := c.pass.Fset.File(stmt.Pos())
+	start := tf.LineStart(tf.Line(stmt.Pos()))
+	end := stmt.End()
+	if line := tf.Line(end); line < tf.LineCount() {
+		end = tf.LineStart(line + 1)
+	}
+	return []analysis.SuggestedFix{{
+		Message:   fmt.Sprintf("Remove the route %s %s replaced by the duplicate", replaced.Method, replaced.Pattern),
+		TextEdits: []analysis.TextEdit{{Pos: start, End: end}},
+	}}
+}
+
+// enclosingStmt returns the innermost statement in a block of the router files containing pos.
+func (c *checker) enclosingStmt(pos token.Pos) ast.Stmt {
+	for _, f := range c.files {
+		var stmt ast.Stmt
+		ast.Inspect(f, func(n ast.Node) bool {
+			if n == nil || n.Pos() > pos || n.End() <= pos {
+				return n == nil
+			}
+			if block, ok := n.(*ast.BlockStmt); ok {
+				for _, s := range block.List {
+					if s.Pos() <= pos && pos < s.End() {
+						stmt = s
+					}
+				}
+			}
+			return true
+		})
+		if stmt != nil {
+			return stmt
+		}
+	}
+	return nil
+}
+
+// checkHandlers reports the handlers of the routes whose parameters do not match the path params
+// passed by the router generated with the options.
+// The handlers not found in the package and its imports are not checked,
+// nor are the handlers with -interface, which the compiler checks against the generated Handlers.
+func (c *checker) checkHandlers() {
+	if opts.Interface {
+		return
+	}
+	for _, rt := range c.spec.Routes {
+		fn := c.lookupFunc(rt.Handler)
+		if fn == nil {
+			continue
+		}
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok {
+			continue
+		}
+		params := rt.Params()
+		var want int
+		switch opts.Params {
+		case gen.ParamsContext:
+			want = 2
+		case gen.ParamsStruct:
+			want = 2
+			if len(params) > 0 {
+				want = 3
+			}
+		default:
+			want = 2 + len(params)
+		}
+		if got := sig.Params().Len(); got != want {
+			c.pass.Reportf(c.pos(rt.Pos), "handler %s takes %d parameters, want %d for %s %s with %d path params",
+				rt.Handler, got, want, rt.Method, rt.Pattern, len(params))
+			continue
+		}
+		if opts.Params != "" && opts.Params != gen.ParamsPositional {
+			continue
+		}
+		for i, name := range params {
+			if p := sig.Params().At(2 + i); !types.Identical(p.Type(), types.Typ[types.String]) {
+				c.pass.Reportf(c.pos(rt.Pos), "handler %s takes the path param %s of %s %s as %s, want string",
+					rt.Handler, name, rt.Method, rt.Pattern, p.Type())
+			}
+		}
+	}
+}
+
+// lookupFunc returns the function of the handler declared in the package or the packages imported by it.
+func (c *checker) lookupFunc(h gen.Handler) *types.Func {
+	scope := c.pass.Pkg.Scope()
+	if h.Package != "" {
+		scope = nil
+		importPath := c.importPath(h.Package)
+		for _, pkg := range c.pass.Pkg.Imports() {
+			if pkg.Path() == importPath {
+				scope = pkg.Scope()
+			}
+		}
+		if scope == nil {
+			return nil
+		}
+	}
+	fn, _ := scope.Lookup(h.Func).(*types.Func)
+	return fn
+}
+
+// importPath returns the import path of the package named name in the router files.
+func (c *checker) importPath(name string) string {
+	for _, p := range c.spec.Imports {
+		n, ok := c.spec.ImportNames[p]
+		if !ok {
+			n = path.Base(p)
+		}
+		if n == name {
+			return p
+		}
+	}
+	return ""
+}
+
+// checkFallbacks reports the NotFound and MethodNotAllowed handlers not declared in the router files.
+func (c *checker) checkFallbacks() {
+	pos := c.pos(c.spec.Pos)
+	if c.spec.NotFound == nil {
+		c.pass.Report(analysis.Diagnostic{
+			Pos:            pos,
+			Message:        "HandleNotFound is not declared; the built-in handler replies with 404 Not Found",
+			SuggestedFixes: c.notFoundFixes(pos),
+		})
+	}
+	if c.spec.MethodNotAllowed == nil {
+		c.pass.Reportf(pos, "HandleMethodNotAllowed is not declared; the built-in handler replies with 405 Method Not Allowed")
+	}
+}
+
+// notFoundFixes returns the fix declaring http.NotFound as the NotFound handler after the router is created at pos.
+func (c *checker) notFoundFixes(pos token.Pos) []analysis.SuggestedFix {
+	stmt := c.enclosingStmt(pos)
+	if stmt == nil || c.importPath("http") != "net/http" {
+		return nil
+	}
+	tf := c.pass.Fset.File(stmt.Pos())
+	src := c.srcs[tf.Name()]
+	indent := src[tf.Offset(tf.LineStart(tf.Line(stmt.Pos()))):tf.Offset(stmt.Pos())]
+	return []analysis.SuggestedFix{{
+		Message: "Declare http.NotFound as the NotFound handler",
+		TextEdits: []analysis.TextEdit{{
+			Pos:     stmt.End(),
+			End:     stmt.End(),
+			NewText: []byte(fmt.Sprintf("\n%s%s.HandleNotFound(http.NotFound)", indent, c.spec.RouterName)),
+		}},
+	}}
+}
+
+// checkGenerated reports the generated files which differ from the files generated from the router files with the options.
+// The generated test is checked only in the test variant of the package, where it is found.
+func (c *checker) checkGenerated() error {
+	o := opts
+	// the flags of the files are written to the go:generate directive as stdrouter does
+	var args []string
+	c.pass.Analyzer.Flags.Visit(func(f *flag.Flag) {
+		if f.Name == "i" || f.Name == "o" || f.Name == "templates" {
+			args = append(args, "-"+f.Name+"="+f.Value.String())
+		}
+	})
+	o.Directive = cli.Directive(o, args)
+	if templateDir != "" {
+		tpls, err := gen.LoadTemplates(templateDir)
+		if err != nil {
+			return fmt.Errorf("gen.LoadTemplates -> %w", err)
+		}
+		o.Templates = tpls
+	}
+	src, err := gen.Generate(c.spec, o)
+	if err != nil {
+		c.pass.Reportf(c.pos(token.Position{}), "failed to generate the router: %v", err)
+		return nil
+	}
+	if err := c.compareGenerated(outputFileName, src, true); err != nil {
+		return err
+	}
+	if !o.Testable {
+		return nil
+	}
+	src, err = gen.GenerateTest(c.spec, o)
+	if err != nil {
+		c.pass.Reportf(c.pos(token.Position{}), "failed to generate the test of the router: %v", err)
+		return nil
+	}
+	return c.compareGenerated(strings.TrimSuffix(outputFileName, ".go")+"_test.go", src, false)
+}
+
+// compareGenerated reports the file named name in the package if it differs from src.
+// The missing file is reported if required.
+// No fix is suggested since the drivers do not apply the fixes to the generated files.
+func (c *checker) compareGenerated(name string, src []byte, required bool) error {
+	filename := name
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(filepath.Dir(c.filenames[0]), name)
+	}
+	var tf *token.File
+	for _, f := range c.pass.Files {
+		if t := c.pass.Fset.File(f.Pos()); filepath.Clean(t.Name()) == filepath.Clean(filename) {
+			tf = t
+		}
+	}
+	for _, ignored := range c.pass.IgnoredFiles {
+		if tf == nil && filepath.Clean(ignored) == filepath.Clean(filename) {
+			b, err := readFile(c.pass, ignored)
+			if err != nil {
+				return fmt.Errorf("failed to read generated file: %w", err)
+			}
+			tf = c.pass.Fset.AddFile(ignored, -1, len(b))
+			tf.SetLinesForContent(b)
+		}
+	}
+	if tf == nil {
+		if required {
+			c.pass.Reportf(c.pos(token.Position{}), "%s is not generated; run stdrouter", name)
+		}
+		return nil
+	}
+	b, err := readFile(c.pass, tf.Name())
+	if err != nil {
+		return fmt.Errorf("failed to read generated file: %w", err)
+	}
+	if !bytes.Equal(b, src) {
+		c.pass.Reportf(tf.Pos(0), "%s is not up to date with the router file; run stdrouter", name)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package vet
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+// recorder records the errors of analysistest except the unexpected diagnostics,
+// since the "want" comments are not read from the router files ignored by the build tag.
+type recorder struct {
+	t *testing.T
+}
+
+func (r recorder) Errorf(format string, args ...interface{}) {
+	if msg := fmt.Sprintf(format, args...); !strings.Contains(msg, ": unexpected diagnostic: ") {
+		r.t.Error(msg)
+	}
+}
+
+func TestAnalyzer(t *testing.T) {
+	tests := []struct {
+		name string
+		pkg  string
+		want []string
+	}{
+		{
+			name: "valid",
+			pkg:  "valid",
+		},
+		{
+			name: "conflicts and handlers",
+			pkg:  "conflict",
+			want: []string{
+				"router.go:12: HandleNotFound is not declared; the built-in handler replies with 404 Not Found [Declare http.NotFound as the NotFound handler]",
+				"router.go:12: HandleMethodNotAllowed is not declared; the built-in handler replies with 405 Method Not Allowed",
+				"router.go:14: handler GetUser takes 2 parameters, want 3 for GET /users/:user_id with 1 path params",
+				"router.go:15: path param :id of /users/:id/posts conflicts with :user_id of GET /users/:user_id",
+				"router.go:16: duplicate route GET /users replaces the handler GetUsers declared before [Remove the route GET /users replaced by the duplicate]",
+				"router.go:17: handler GetPost takes the path param post_id of GET /posts/:post_id as int, want string",
+			},
+		},
+		{
+			name: "unknown methods",
+			pkg:  "method",
+			want: []string{
+				"router.go:13: method not found. got: MethodGot [Replace with http.MethodGet]",
+				"router.go:14: method not found. got: MethodPOST [Replace with http.MethodPost]",
+				"router.go:15: method not found. got: MethodFoo",
+			},
+		},
+		{
+			name: "stale router_gen.go",
+			pkg:  "stale",
+			want: []string{
+				"router_gen.go:1: router_gen.go is not up to date with the router file; run stdrouter",
+			},
+		},
+		{
+			name: "missing router_gen.go and fallbacks",
+			pkg:  "missing",
+			want: []string{
+				"router.go:12: HandleNotFound is not declared; the built-in handler replies with 404 Not Found [Declare http.NotFound as the NotFound handler]",
+				"router.go:12: HandleMethodNotAllowed is not declared; the built-in handler replies with 405 Method Not Allowed",
+				"router.go:3: router_gen.go is not generated; run stdrouter",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := analysistest.Run(recorder{t}, analysistest.TestData(), Analyzer, tt.pkg)
+			var got []string
+			for _, res := range results {
+				for _, d := range res.Diagnostics {
+					posn := res.Pass.Fset.Position(d.Pos)
+					s := fmt.Sprintf("%s:%d: %s", filepath.Base(posn.Filename), posn.Line, d.Message)
+					var fixes []string
+					for _, fix := range d.SuggestedFixes {
+						fixes = append(fixes, fix.Message)
+					}
+					if len(fixes) > 0 {
+						s += " [" + strings.Join(fixes, ", ") + "]"
+					}
+					got = append(got, s)
+				}
+			}
+			// the order of the diagnostics depends on the checks
+			sort.Strings(got)
+			sort.Strings(tt.want)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diagnostics = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
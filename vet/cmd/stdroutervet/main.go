@@ -0,0 +1,18 @@
+// Command stdroutervet checks the router files of stdrouter in the packages.
+//
+//	$ stdroutervet ./...
+//
+// It also runs in go vet:
+//
+//	$ go vet -vettool=$(which stdroutervet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/multichecker"
+
+	"github.com/tetsuzawa/stdrouter/vet"
+)
+
+func main() {
+	multichecker.Main(vet.Analyzer)
+}
@@ -0,0 +1,13 @@
+package conflict
+
+import "net/http"
+
+func GetUsers(w http.ResponseWriter, r *http.Request) {}
+
+func ListUsers(w http.ResponseWriter, r *http.Request) {}
+
+func GetUser(w http.ResponseWriter, r *http.Request) {}
+
+func GetPosts(w http.ResponseWriter, r *http.Request, id string) {}
+
+func GetPost(w http.ResponseWriter, r *http.Request, postID int) {}
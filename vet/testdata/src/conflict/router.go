@@ -0,0 +1,19 @@
+//go:build stdrouter
+
+package conflict
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/users", http.MethodGet, GetUsers)
+	r.HandleFunc("/users/:user_id", http.MethodGet, GetUser)
+	r.HandleFunc("/users/:id/posts", http.MethodGet, GetPosts)
+	r.HandleFunc("/users", http.MethodGet, ListUsers)
+	r.HandleFunc("/posts/:post_id", http.MethodGet, GetPost)
+	return r
+}
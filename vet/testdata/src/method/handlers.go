@@ -0,0 +1,7 @@
+package method
+
+import "net/http"
+
+func GetUsers(w http.ResponseWriter, r *http.Request) {}
+
+func CreateUser(w http.ResponseWriter, r *http.Request) {}
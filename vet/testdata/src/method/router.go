@@ -0,0 +1,19 @@
+//go:build stdrouter
+
+package method
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/users", http.MethodGot, GetUsers)
+	r.HandleFunc("/users", http.MethodPOST, CreateUser)
+	r.HandleFunc("/users", http.MethodFoo, CreateUser)
+	r.HandleNotFound(http.NotFound)
+	r.HandleMethodNotAllowed(http.NotFound)
+	return r
+}
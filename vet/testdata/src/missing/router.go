@@ -0,0 +1,16 @@
+//go:build stdrouter
+
+package missing
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/users", http.MethodGet, GetUsers)
+	r.HandleFunc("/users/:user_id", http.MethodGet, GetUser)
+	return r
+}
@@ -0,0 +1,9 @@
+package valid
+
+import "net/http"
+
+func GetUsers(w http.ResponseWriter, r *http.Request) {}
+
+func GetUser(w http.ResponseWriter, r *http.Request, userID string) {}
+
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {}
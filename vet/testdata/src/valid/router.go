@@ -0,0 +1,18 @@
+//go:build stdrouter
+
+package valid
+
+import (
+	"net/http"
+
+	"github.com/tetsuzawa/stdrouter"
+)
+
+func NewRouter() http.Handler {
+	r := stdrouter.NewRouter()
+	r.HandleFunc("/users", http.MethodGet, GetUsers)
+	r.HandleFunc("/users/:user_id", http.MethodGet, GetUser)
+	r.HandleNotFound(http.NotFound)
+	r.HandleMethodNotAllowed(MethodNotAllowed)
+	return r
+}